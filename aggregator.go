@@ -0,0 +1,157 @@
+package health
+
+// Aggregator determines the overall health from the states of the checks (see
+// "Health.Aggregator"). It is used by "Failed()", "State()", "Status()" and
+// (for the states of a single group) "FailedFor()" and "StateFor()".
+type Aggregator interface {
+	// Failed returns true if the overall health has failed, given the states
+	// of the checks (keyed by the name of the check)
+	Failed(states map[string]State) bool
+}
+
+// AggregatorFunc adapts an ordinary function to the Aggregator interface.
+type AggregatorFunc func(states map[string]State) bool
+
+// Failed calls f(states).
+func (f AggregatorFunc) Failed(states map[string]State) bool {
+	return f(states)
+}
+
+// FatalAggregator fails if any fatal check has failed (or is stale or was
+// skipped); this is the default aggregator.
+type FatalAggregator struct{}
+
+// Failed returns true if any fatal check is unhealthy.
+func (FatalAggregator) Failed(states map[string]State) bool {
+	for _, val := range states {
+		if val.Fatal && val.isUnhealthy() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// QuorumAggregator fails if more than "MaxFailures" checks have failed (or are
+// stale or were skipped), regardless of whether they are fatal. It is meant to
+// be used for a group of replicas (see "GroupedAggregator").
+type QuorumAggregator struct {
+	MaxFailures int
+}
+
+// Failed returns true if more than "MaxFailures" checks are unhealthy.
+func (q QuorumAggregator) Failed(states map[string]State) bool {
+	failures := 0
+
+	for _, val := range states {
+		if val.isUnhealthy() {
+			failures++
+		}
+	}
+
+	return failures > q.MaxFailures
+}
+
+// AllFailAggregator fails only if every check has failed (or is stale or was
+// skipped), regardless of whether they are fatal; ie. for redundant
+// dependencies. It never fails without any states.
+type AllFailAggregator struct{}
+
+// Failed returns true if all of the checks are unhealthy.
+func (AllFailAggregator) Failed(states map[string]State) bool {
+	if len(states) == 0 {
+		return false
+	}
+
+	for _, val := range states {
+		if !val.isUnhealthy() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WeightedAggregator assigns a weight to every check and fails once the
+// summed weight of the failed (or stale or skipped) checks reaches "Threshold".
+type WeightedAggregator struct {
+	// Weights of the checks, keyed by the name of the check
+	Weights map[string]float64
+
+	// DefaultWeight is the weight of the checks missing from "Weights"
+	DefaultWeight float64
+
+	// Threshold is the summed weight at which the overall health fails
+	Threshold float64
+}
+
+// Failed returns true if the summed weight of the unhealthy checks reaches the
+// threshold.
+func (w WeightedAggregator) Failed(states map[string]State) bool {
+	score := 0.0
+
+	for name, val := range states {
+		if !val.isUnhealthy() {
+			continue
+		}
+
+		weight, ok := w.Weights[name]
+		if !ok {
+			weight = w.DefaultWeight
+		}
+
+		score += weight
+	}
+
+	return score > 0 && score >= w.Threshold
+}
+
+// GroupedAggregator aggregates the states of each group (see "Config.Groups")
+// with its own aggregator; it fails if any of the groups fails. The states of
+// the checks that are not a member of any of the groups are aggregated via
+// "Default" (or "FatalAggregator" if not set).
+type GroupedAggregator struct {
+	Groups  map[string]Aggregator
+	Default Aggregator
+}
+
+// Failed returns true if any of the groups (or the remaining checks) failed.
+func (g GroupedAggregator) Failed(states map[string]State) bool {
+	rest := make(map[string]State, 0)
+
+	for name, val := range states {
+		grouped := false
+
+		for group := range g.Groups {
+			if val.inGroup(group) {
+				grouped = true
+				break
+			}
+		}
+
+		if !grouped {
+			rest[name] = val
+		}
+	}
+
+	for group, aggregator := range g.Groups {
+		groupStates := make(map[string]State, 0)
+
+		for name, val := range states {
+			if val.inGroup(group) {
+				groupStates[name] = val
+			}
+		}
+
+		if len(groupStates) > 0 && aggregator.Failed(groupStates) {
+			return true
+		}
+	}
+
+	def := g.Default
+	if def == nil {
+		def = FatalAggregator{}
+	}
+
+	return def.Failed(rest)
+}
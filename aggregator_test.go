@@ -0,0 +1,96 @@
+package health
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAggregators(t *testing.T) {
+	RegisterTestingT(t)
+
+	states := map[string]State{
+		"primary":  {Name: "primary", Status: StatusFailed, Groups: []string{"replicas"}},
+		"replica1": {Name: "replica1", Status: StatusOK, Groups: []string{"replicas"}},
+		"replica2": {Name: "replica2", Status: StatusStale, Groups: []string{"replicas"}},
+		"db":       {Name: "db", Status: StatusOK, Fatal: true},
+		"cache":    {Name: "cache", Status: StatusSkipped},
+	}
+
+	t.Run("FatalAggregator should fail if a fatal check is unhealthy", func(t *testing.T) {
+		Expect(FatalAggregator{}.Failed(states)).To(BeFalse())
+
+		Expect(FatalAggregator{}.Failed(map[string]State{
+			"db": {Name: "db", Status: StatusSkipped, Fatal: true},
+		})).To(BeTrue())
+	})
+
+	t.Run("QuorumAggregator should fail if too many checks are unhealthy", func(t *testing.T) {
+		Expect(QuorumAggregator{MaxFailures: 3}.Failed(states)).To(BeFalse())
+		Expect(QuorumAggregator{MaxFailures: 2}.Failed(states)).To(BeTrue())
+	})
+
+	t.Run("AllFailAggregator should only fail if every check is unhealthy", func(t *testing.T) {
+		Expect(AllFailAggregator{}.Failed(states)).To(BeFalse())
+		Expect(AllFailAggregator{}.Failed(map[string]State{})).To(BeFalse())
+
+		Expect(AllFailAggregator{}.Failed(map[string]State{
+			"primary":  states["primary"],
+			"replica2": states["replica2"],
+		})).To(BeTrue())
+	})
+
+	t.Run("WeightedAggregator should fail once the threshold is reached", func(t *testing.T) {
+		w := WeightedAggregator{
+			Weights:       map[string]float64{"primary": 0.5},
+			DefaultWeight: 0.1,
+			Threshold:     0.8,
+		}
+
+		Expect(w.Failed(states)).To(BeFalse())
+
+		w.Threshold = 0.7
+		Expect(w.Failed(states)).To(BeTrue())
+	})
+
+	t.Run("AggregatorFunc should call the func", func(t *testing.T) {
+		f := AggregatorFunc(func(s map[string]State) bool {
+			return len(s) == len(states)
+		})
+
+		Expect(f.Failed(states)).To(BeTrue())
+	})
+
+	t.Run("GroupedAggregator should aggregate each group on its own", func(t *testing.T) {
+		g := GroupedAggregator{
+			Groups: map[string]Aggregator{
+				"replicas": QuorumAggregator{MaxFailures: 2},
+			},
+		}
+
+		Expect(g.Failed(states)).To(BeFalse())
+
+		g.Groups["replicas"] = QuorumAggregator{MaxFailures: 1}
+		Expect(g.Failed(states)).To(BeTrue())
+
+		// the remaining checks use the default aggregator
+		g.Groups["replicas"] = QuorumAggregator{MaxFailures: 2}
+		g.Default = QuorumAggregator{}
+		Expect(g.Failed(states)).To(BeTrue())
+	})
+}
+
+func TestFailedStates(t *testing.T) {
+	RegisterTestingT(t)
+
+	states := map[string]State{
+		"a": {Name: "a", Status: StatusFailed, Fatal: true},
+		"b": {Name: "b", Status: StatusOK, Fatal: true},
+	}
+
+	h := setupNewTestHealth()
+	Expect(h.FailedStates(states)).To(BeTrue())
+
+	h.Aggregator = AllFailAggregator{}
+	Expect(h.FailedStates(states)).To(BeFalse())
+}
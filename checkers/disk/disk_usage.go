@@ -3,6 +3,7 @@ package diskchk
 import (
 	"fmt"
 
+	"github.com/InVisionApp/go-health/v2"
 	"github.com/shirou/gopsutil/disk"
 )
 
@@ -10,7 +11,7 @@ import (
 //
 // "Path" is _required_; path to check directory/drive (ex. /home/user)
 // "WarningThreshold" is _required_; set percent (more than 0 and less 100) of free space at specified path,
-//  which triggers warning.
+//  which triggers warning (reported as a "*health.Warning", ie. the check is marked as degraded).
 // "CriticalThreshold" is _required_; set percent (more than 0 and less 100) of free space at specified path,
 //  which triggers critical.
 type DiskUsageConfig struct {
@@ -54,7 +55,7 @@ func (d *DiskUsage) Status() (interface{}, error) {
 	}
 
 	if diskUsage >= d.Config.WarningThreshold {
-		return nil, fmt.Errorf("Warning: disk usage too high %.2f percent", diskUsage)
+		return nil, &health.Warning{Err: fmt.Errorf("Warning: disk usage too high %.2f percent", diskUsage)}
 	}
 
 	return nil, nil
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/InVisionApp/go-health/v2"
 	. "github.com/onsi/gomega"
 )
 
@@ -134,6 +135,7 @@ func TestDiskUsageStatus(t *testing.T) {
 		_, err = du.Status()
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("Warning: disk usage too high"))
+		Expect(err).To(BeAssignableToTypeOf(&health.Warning{}))
 	})
 
 	t.Run("Shouldn't return error when everything is ok", func(t *testing.T) {
@@ -2,6 +2,7 @@ package checkers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -64,7 +65,13 @@ func NewHTTP(cfg *HTTPConfig) (*HTTP, error) {
 // Status is used for performing an HTTP check against a dependency; it satisfies
 // the "ICheckable" interface.
 func (h *HTTP) Status() (interface{}, error) {
-	resp, err := h.do()
+	return h.StatusContext(context.Background())
+}
+
+// StatusContext is the context aware variant of "Status()"; it satisfies the
+// "IContextCheckable" interface and aborts the request once "ctx" is done.
+func (h *HTTP) StatusContext(ctx context.Context) (interface{}, error) {
+	resp, err := h.do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +99,7 @@ func (h *HTTP) Status() (interface{}, error) {
 	return nil, nil
 }
 
-func (h *HTTP) do() (*http.Response, error) {
+func (h *HTTP) do(ctx context.Context) (*http.Response, error) {
 	payload, err := parsePayload(h.Config.Payload)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing payload: %v", err)
@@ -103,7 +110,7 @@ func (h *HTTP) do() (*http.Response, error) {
 		return nil, fmt.Errorf("Unable to create new HTTP request for HTTPMonitor check: %v", err)
 	}
 
-	resp, err := h.Config.Client.Do(req)
+	resp, err := h.Config.Client.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("Ran into error while performing '%v' request: %v", h.Config.Method, err)
 	}
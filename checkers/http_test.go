@@ -1,6 +1,7 @@
 package checkers
 
 import (
+	"context"
 	"math"
 	"net/http"
 	"net/http/httptest"
@@ -52,7 +53,7 @@ func TestDo(t *testing.T) {
 			},
 		}
 
-		res, err := h.do()
+		res, err := h.do(context.Background())
 		Expect(err).To(HaveOccurred())
 		Expect(res).To(BeNil())
 		Expect(err.Error()).To(ContainSubstring("error parsing payload"))
@@ -68,7 +69,7 @@ func TestDo(t *testing.T) {
 			},
 		}
 
-		res, err := h.do()
+		res, err := h.do(context.Background())
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("Unable to create new HTTP request for HTTPMonitor check"))
 		Expect(res).To(BeNil())
@@ -285,6 +286,34 @@ func TestHTTPStatus(t *testing.T) {
 	})
 }
 
+func TestHTTPStatusContext(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return error if context is done before the request completes", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		testURL, err := url.Parse(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		checker, err := NewHTTP(&HTTPConfig{
+			URL: testURL,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		data, err := checker.StatusContext(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("context deadline exceeded"))
+		Expect(data).To(BeNil())
+	})
+}
+
 type CustomTransport struct{}
 
 func newTransport() *CustomTransport {
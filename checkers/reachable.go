@@ -1,6 +1,7 @@
 package checkers
 
 import (
+	"context"
 	"net"
 	"net/url"
 	"time"
@@ -88,6 +89,12 @@ func NewReachableChecker(cfg *ReachableConfig) (*ReachableChecker, error) {
 
 // Status checks if the endpoint is reachable
 func (r *ReachableChecker) Status() (interface{}, error) {
+	return r.StatusContext(context.Background())
+}
+
+// StatusContext checks if the endpoint is reachable; the dial timeout is
+// shortened to the deadline of "ctx" if it expires before the configured timeout
+func (r *ReachableChecker) StatusContext(ctx context.Context) (interface{}, error) {
 	// We must provide a port so when a port is not set in the URL provided use
 	// the default port (80)
 	port := r.url.Port()
@@ -95,7 +102,18 @@ func (r *ReachableChecker) Status() (interface{}, error) {
 		port = ReachableDefaultPort
 	}
 
-	conn, err := r.dialer(r.network, r.url.Hostname()+":"+port, r.timeout)
+	timeout := r.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	if timeout <= 0 {
+		return r.fail(context.DeadlineExceeded)
+	}
+
+	conn, err := r.dialer(r.network, r.url.Hostname()+":"+port, timeout)
 	if err != nil {
 		return r.fail(err)
 	}
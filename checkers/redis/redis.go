@@ -1,6 +1,7 @@
 package redischk
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"time"
@@ -109,21 +110,29 @@ func NewRedis(cfg *RedisConfig) (*Redis, error) {
 // Status is used for performing a redis check against a dependency; it satisfies
 // the "ICheckable" interface.
 func (r *Redis) Status() (interface{}, error) {
+	return r.StatusContext(context.Background())
+}
+
+// StatusContext is the context aware variant of "Status()"; it satisfies the
+// "IContextCheckable" interface and passes "ctx" down to the redis client.
+func (r *Redis) StatusContext(ctx context.Context) (interface{}, error) {
+	client := r.client.WithContext(ctx)
+
 	if r.Config.Ping {
-		if _, err := r.client.Ping().Result(); err != nil {
+		if _, err := client.Ping().Result(); err != nil {
 			return nil, fmt.Errorf("Ping failed: %v", err)
 		}
 	}
 
 	if r.Config.Set != nil {
-		err := r.client.Set(r.Config.Set.Key, r.Config.Set.Value, r.Config.Set.Expiration).Err()
+		err := client.Set(r.Config.Set.Key, r.Config.Set.Value, r.Config.Set.Expiration).Err()
 		if err != nil {
 			return nil, fmt.Errorf("Unable to complete set: %v", err)
 		}
 	}
 
 	if r.Config.Get != nil {
-		val, err := r.client.Get(r.Config.Get.Key).Result()
+		val, err := client.Get(r.Config.Get.Key).Result()
 		if err != nil {
 			if err == redis.Nil {
 				if !r.Config.Get.NoErrorMissingKey {
@@ -141,6 +141,12 @@ func validateSQLConfig(cfg *SQLConfig) error {
 // Status is used for performing a database ping against a dependency; it satisfies
 // the "ICheckable" interface.
 func (s *SQL) Status() (interface{}, error) {
+	return s.StatusContext(context.Background())
+}
+
+// StatusContext is the context aware variant of "Status()"; it satisfies the
+// "IContextCheckable" interface and passes "ctx" down to the database driver.
+func (s *SQL) StatusContext(ctx context.Context) (interface{}, error) {
 	if err := validateSQLConfig(s.Config); err != nil {
 		return nil, err
 	}
@@ -153,7 +159,7 @@ func (s *SQL) Status() (interface{}, error) {
 			s.Config.ExecerResultHandler = DefaultExecHandler
 		}
 		// run the execer
-		return s.runExecer()
+		return s.runExecer(ctx)
 	// check for SQLQueryer next
 	case s.Config.Queryer != nil:
 		// if the result handler is nil, use the default
@@ -161,17 +167,15 @@ func (s *SQL) Status() (interface{}, error) {
 			s.Config.QueryerResultHandler = DefaultQueryHandler
 		}
 		// run the queryer
-		return s.runQueryer()
+		return s.runQueryer(ctx)
 	// finally, must be a pinger
 	default:
-		ctx := context.Background()
 		return nil, s.Config.Pinger.PingContext(ctx)
 	}
 }
 
 // This will run the execer from the Status func
-func (s *SQL) runExecer() (interface{}, error) {
-	ctx := context.Background()
+func (s *SQL) runExecer(ctx context.Context) (interface{}, error) {
 	result, err := s.Config.Execer.ExecContext(ctx, s.Config.Query, s.Config.Params...)
 	if err != nil {
 		return nil, err
@@ -190,8 +194,7 @@ func (s *SQL) runExecer() (interface{}, error) {
 }
 
 // This will run the queryer from the Status func
-func (s *SQL) runQueryer() (interface{}, error) {
-	ctx := context.Background()
+func (s *SQL) runQueryer(ctx context.Context) (interface{}, error) {
 	rows, err := s.Config.Queryer.QueryContext(ctx, s.Config.Query, s.Config.Params...)
 	if err != nil {
 		return nil, err
@@ -18,6 +18,10 @@ type testHealthyPinger struct{}
 
 type testUnhealthyPinger struct{}
 
+type testContextPinger struct {
+	ctx context.Context
+}
+
 type nilExecer struct{}
 
 type nilQueryer struct{}
@@ -32,6 +36,11 @@ func (p *testUnhealthyPinger) PingContext(ctx context.Context) error {
 	return fmt.Errorf("ping failed")
 }
 
+func (p *testContextPinger) PingContext(ctx context.Context) error {
+	p.ctx = ctx
+	return ctx.Err()
+}
+
 func (e *nilExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	return nil, nil
 }
@@ -193,6 +202,21 @@ func TestSQLStatus(t *testing.T) {
 		_, err := s.Status()
 		Expect(err).To(HaveOccurred())
 	})
+
+	t.Run("context is passed to the pinger", func(t *testing.T) {
+		db := &testContextPinger{}
+		s, err := NewSQL(&SQLConfig{
+			Pinger: db,
+		})
+		Expect(err).To(BeNil())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = s.StatusContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(db.ctx).To(Equal(ctx))
+	})
 }
 
 func TestDefaultExecHandler(t *testing.T) {
@@ -240,7 +264,7 @@ func TestRunExecer(t *testing.T) {
 		})
 		Expect(err).To(BeNil())
 
-		_, err = s.runExecer()
+		_, err = s.runExecer(context.Background())
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("exec error"))
 	})
@@ -259,7 +283,7 @@ func TestRunExecer(t *testing.T) {
 		})
 		Expect(err).To(BeNil())
 
-		_, err = s.runExecer()
+		_, err = s.runExecer(context.Background())
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("exec handler failure"))
 	})
@@ -278,7 +302,7 @@ func TestRunExecer(t *testing.T) {
 		})
 		Expect(err).To(BeNil())
 
-		_, err = s.runExecer()
+		_, err = s.runExecer(context.Background())
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("userland exec result handler returned false"))
 	})
@@ -323,7 +347,7 @@ func TestRunQueryer(t *testing.T) {
 		})
 		Expect(err).To(BeNil())
 
-		_, err = s.runQueryer()
+		_, err = s.runQueryer(context.Background())
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("query error"))
 	})
@@ -344,7 +368,7 @@ func TestRunQueryer(t *testing.T) {
 		})
 		Expect(err).To(BeNil())
 
-		_, err = s.runQueryer()
+		_, err = s.runQueryer(context.Background())
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("query handler failure"))
 	})
@@ -365,7 +389,7 @@ func TestRunQueryer(t *testing.T) {
 		})
 		Expect(err).To(BeNil())
 
-		_, err = s.runQueryer()
+		_, err = s.runQueryer(context.Background())
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("userland query result handler returned false"))
 	})
@@ -0,0 +1,101 @@
+// Package clock abstracts the passing of time, so that the scheduling of
+// health checks can be driven deterministically in tests (see
+// "fakes.FakeClock").
+package clock
+
+import (
+	"time"
+)
+
+// Clock provides the current time and the timers used to schedule checks.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+
+	// NewTimer creates a timer that fires once after d
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker creates a ticker that fires every d
+	NewTicker(d time.Duration) Ticker
+
+	// After returns a channel that receives the current time after d
+	After(d time.Duration) <-chan time.Time
+
+	// AfterFunc calls f in its own goroutine after d; the returned timer can
+	// be used to cancel the call
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event, like "time.Timer".
+type Timer interface {
+	// C returns the channel the time is delivered on; nil for timers created
+	// by "Clock.AfterFunc()"
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing; returns false if it already fired
+	// or was stopped
+	Stop() bool
+
+	// Reset changes the timer to fire after d; returns false if it already
+	// fired or was stopped
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, like "time.Ticker".
+type Ticker interface {
+	// C returns the channel the ticks are delivered on
+	C() <-chan time.Time
+
+	// Stop turns off the ticker
+	Stop()
+}
+
+// New returns a Clock backed by the "time" package.
+func New() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestClock(t *testing.T) {
+	RegisterTestingT(t)
+
+	c := New()
+
+	t.Run("Should report the system time", func(t *testing.T) {
+		before := time.Now()
+		now := c.Now()
+
+		Expect(now).To(BeTemporally(">=", before))
+		Expect(c.Since(before)).To(BeNumerically(">=", 0))
+	})
+
+	t.Run("Should fire timers", func(t *testing.T) {
+		timer := c.NewTimer(time.Millisecond)
+		Eventually(timer.C()).Should(Receive())
+		Expect(timer.Stop()).To(BeFalse())
+
+		Eventually(c.After(time.Millisecond)).Should(Receive())
+
+		ticker := c.NewTicker(time.Millisecond)
+		Eventually(ticker.C()).Should(Receive())
+		ticker.Stop()
+
+		called := make(chan struct{})
+		c.AfterFunc(time.Millisecond, func() { close(called) })
+		Eventually(called).Should(BeClosed())
+	})
+}
@@ -0,0 +1,59 @@
+package health
+
+// validates the "DependsOn" of the given checks; every dependency must have
+// been added and the dependencies must not form a cycle
+func validateDependencies(cfgs []*Config) error {
+	byName := make(map[string]*Config, len(cfgs))
+
+	for _, c := range cfgs {
+		byName[c.Name] = c
+	}
+
+	for _, c := range cfgs {
+		for _, dep := range c.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return ErrUnknownDependency
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	marks := make(map[string]int, len(cfgs))
+
+	var visit func(c *Config) bool
+
+	// depth-first search; returns false if a cycle is found
+	visit = func(c *Config) bool {
+		switch marks[c.Name] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+
+		marks[c.Name] = visiting
+
+		for _, dep := range c.DependsOn {
+			if !visit(byName[dep]) {
+				return false
+			}
+		}
+
+		marks[c.Name] = visited
+
+		return true
+	}
+
+	for _, c := range cfgs {
+		if !visit(c) {
+			return ErrDependencyCycle
+		}
+	}
+
+	return nil
+}
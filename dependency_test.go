@@ -0,0 +1,38 @@
+package health
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should accept valid dependencies", func(t *testing.T) {
+		Expect(validateDependencies([]*Config{
+			{Name: "network"},
+			{Name: "db", DependsOn: []string{"network"}},
+			{Name: "cache", DependsOn: []string{"network"}},
+			{Name: "api", DependsOn: []string{"db", "cache"}},
+		})).To(Succeed())
+	})
+
+	t.Run("Should reject unknown dependencies", func(t *testing.T) {
+		Expect(validateDependencies([]*Config{
+			{Name: "db", DependsOn: []string{"network"}},
+		})).To(Equal(ErrUnknownDependency))
+	})
+
+	t.Run("Should reject cycles", func(t *testing.T) {
+		Expect(validateDependencies([]*Config{
+			{Name: "a", DependsOn: []string{"c"}},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c", DependsOn: []string{"b"}},
+		})).To(Equal(ErrDependencyCycle))
+
+		Expect(validateDependencies([]*Config{
+			{Name: "a", DependsOn: []string{"a"}},
+		})).To(Equal(ErrDependencyCycle))
+	})
+}
@@ -0,0 +1,152 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// SubscriberBufferSize is the number of events buffered for every subscriber
+// (see "Health.Subscribe()").
+const SubscriberBufferSize = 64
+
+// EventType is the type of an "Event".
+type EventType string
+
+const (
+	// EventResult is published whenever a result of a check is recorded
+	EventResult EventType = "result"
+
+	// EventFailed is published when a check transitions to "failed" (see
+	// "IStatusListener.HealthCheckFailed")
+	EventFailed EventType = "failed"
+
+	// EventRecovered is published when a check recovers from "failed" (see
+	// "IStatusListener.HealthCheckRecovered")
+	EventRecovered EventType = "recovered"
+
+	// EventDegraded is published when a check transitions to "warn" (see
+	// "IDegradedStatusListener.HealthCheckDegraded")
+	EventDegraded EventType = "degraded"
+
+	// EventStale is published when a check goes stale (see
+	// "IStaleStatusListener.HealthCheckStale")
+	EventStale EventType = "stale"
+
+	// EventAdded is published when a check is added
+	EventAdded EventType = "added"
+
+	// EventRemoved is published when a check is removed
+	EventRemoved EventType = "removed"
+)
+
+// Event describes something that happened to a check.
+type Event struct {
+	// Type of the event
+	Type EventType
+
+	// Name of the check
+	Name string
+
+	// State of the check at the time of the event; empty for "EventAdded" and
+	// "EventRemoved"
+	State State
+
+	// Time the event was published at
+	Time time.Time
+
+	// Dropped is the number of events that were dropped for this subscriber
+	// (since its buffer was full) right before this event
+	Dropped int
+}
+
+// EventFilter selects the events delivered to a subscriber; a nil filter
+// selects all events.
+type EventFilter func(e Event) bool
+
+// EventTypes returns a filter selecting the events of the given types.
+func EventTypes(types ...EventType) EventFilter {
+	return func(e Event) bool {
+		for _, t := range types {
+			if e.Type == t {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+type subscriber struct {
+	ch      chan Event
+	filter  EventFilter
+	dropped int
+}
+
+// Subscribe returns a channel that receives the events (selected by filter)
+// of all checks, in the order they happened, along with a func that cancels
+// the subscription and closes the channel. The transition events of a run
+// ("EventFailed", "EventRecovered", "EventDegraded") precede its "EventResult".
+//
+// Every subscriber has a buffer of "SubscriberBufferSize" events. Events are
+// never blocked on: if the buffer of a subscriber is full, new events are
+// dropped for that subscriber until it catches up; the number of dropped
+// events is reported via "Event.Dropped" of the next delivered event.
+func (h *Health) Subscribe(filter EventFilter) (<-chan Event, func()) {
+	sub := &subscriber{
+		ch:     make(chan Event, SubscriberBufferSize),
+		filter: filter,
+	}
+
+	h.subsLock.Lock()
+	h.subs[sub] = struct{}{}
+	h.subsLock.Unlock()
+
+	var once sync.Once
+
+	cancel := func() {
+		once.Do(func() {
+			h.subsLock.Lock()
+			defer h.subsLock.Unlock()
+
+			delete(h.subs, sub)
+			close(sub.ch)
+		})
+	}
+
+	return sub.ch, cancel
+}
+
+// publishes an event to all subscribers, without blocking
+func (h *Health) publish(typ EventType, name string, stateEntry *State) {
+	h.subsLock.Lock()
+	defer h.subsLock.Unlock()
+
+	if len(h.subs) == 0 {
+		return
+	}
+
+	e := Event{
+		Type: typ,
+		Name: name,
+		Time: h.clock().Now(),
+	}
+
+	if stateEntry != nil {
+		e.State = *stateEntry
+	}
+
+	for sub := range h.subs {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+
+		e.Dropped = sub.dropped
+
+		select {
+		case sub.ch <- e:
+			sub.dropped = 0
+		default:
+			sub.dropped++
+		}
+	}
+}
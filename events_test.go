@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2/fakes"
+)
+
+// collects events from ch until it has been idle for a while
+func drainEvents(ch <-chan Event) []Event {
+	var events []Event
+
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return events
+			}
+
+			events = append(events, e)
+		case <-time.After(50 * time.Millisecond):
+			return events
+		}
+	}
+}
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, 0, len(events))
+
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+
+	return types
+}
+
+func TestSubscribe(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should deliver lifecycle, result and transition events in order", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		events, cancel := h.Subscribe(nil)
+		defer cancel()
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, errors.New("down"))
+
+		Expect(h.AddCheck(&Config{Name: "foo", Checker: checker, Interval: time.Hour})).To(Succeed())
+		Expect(h.Start()).To(Succeed())
+
+		Eventually(func() int { return checker.StatusCallCount() }).Should(Equal(1))
+
+		_, err := h.RunNow("foo")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(h.Stop()).To(Succeed())
+		Expect(h.RemoveCheck("foo")).To(Succeed())
+
+		received := drainEvents(events)
+		Expect(eventTypes(received)).To(Equal([]EventType{
+			EventAdded, EventFailed, EventResult, EventRecovered, EventResult, EventRemoved,
+		}))
+
+		for _, e := range received {
+			Expect(e.Name).To(Equal("foo"))
+			Expect(e.Time.IsZero()).To(BeFalse())
+		}
+
+		Expect(received[2].State.Status).To(Equal(StatusFailed))
+		Expect(received[4].State.Status).To(Equal(StatusOK))
+	})
+
+	t.Run("Should only deliver the events selected by the filter", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		events, cancel := h.Subscribe(EventTypes(EventAdded))
+		defer cancel()
+
+		Expect(h.AddChecks([]*Config{
+			{Name: "foo", Checker: &fakes.FakeICheckable{}, Interval: time.Hour},
+			{Name: "bar", Checker: &fakes.FakeICheckable{}, Interval: time.Hour},
+		})).To(Succeed())
+
+		_, _, err := h.CheckOnce(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+
+		received := drainEvents(events)
+		Expect(eventTypes(received)).To(Equal([]EventType{EventAdded, EventAdded}))
+		Expect(received[0].Name).To(Equal("foo"))
+		Expect(received[1].Name).To(Equal("bar"))
+	})
+
+	t.Run("Should drop new events when the buffer is full and report them", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		events, cancel := h.Subscribe(nil)
+		defer cancel()
+
+		for i := 0; i < SubscriberBufferSize+3; i++ {
+			h.publish(EventResult, "foo", &State{Name: "foo", Details: i})
+		}
+
+		for i := 0; i < SubscriberBufferSize; i++ {
+			e := <-events
+			Expect(e.State.Details).To(Equal(i))
+			Expect(e.Dropped).To(BeZero())
+		}
+
+		h.publish(EventResult, "foo", &State{Name: "foo", Details: "next"})
+
+		e := <-events
+		Expect(e.State.Details).To(Equal("next"))
+		Expect(e.Dropped).To(Equal(3))
+	})
+
+	t.Run("Should close the channel on cancel", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		events, cancel := h.Subscribe(nil)
+		cancel()
+		cancel()
+
+		_, ok := <-events
+		Expect(ok).To(BeFalse())
+
+		// publishing without subscribers is a no-op
+		h.publish(EventAdded, "foo", nil)
+	})
+}
@@ -0,0 +1,228 @@
+package fakes
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/InVisionApp/go-health/v2/clock"
+)
+
+// FakeClock is a hand-written "clock.Clock" whose time only moves when
+// "Advance()" is called, firing the timers and tickers that are due in the
+// order of their deadlines. Use "BlockUntil()" to wait for the code under test
+// to arm its timers before advancing.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFakeClock returns a fake clock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now returns the current (fake) time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Since returns the (fake) time elapsed since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// NewTimer creates a timer that fires once the clock has been advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	return c.schedule(d, 0, nil)
+}
+
+// NewTicker creates a ticker that fires every time the clock has been advanced
+// by another d; it panics if d is not positive (like "time.NewTicker()").
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+
+	return fakeTicker{c.schedule(d, d, nil)}
+}
+
+// After returns a channel that receives the (fake) time once the clock has
+// been advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// AfterFunc calls f in its own goroutine once the clock has been advanced by
+// d; the returned timer can be used to cancel the call.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return c.schedule(d, 0, f)
+}
+
+// Advance moves the time forward by d, firing every timer and ticker that
+// becomes due. Functions registered via "AfterFunc()" are called in their own
+// goroutines, like with the real clock.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+
+	for len(c.waiters) > 0 && !c.waiters[0].at.After(end) {
+		t := c.waiters[0]
+
+		if t.at.After(c.now) {
+			c.now = t.at
+		}
+
+		if t.period > 0 {
+			t.at = t.at.Add(t.period)
+			c.sortWaiters()
+		} else {
+			c.removeWaiter(t)
+		}
+
+		t.fire(c.now)
+	}
+
+	c.now = end
+}
+
+// Waiters returns the number of timers and tickers that have yet to fire.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until there are at least n timers and tickers that have
+// yet to fire.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// creates and registers a timer (or a ticker, if period is positive)
+func (c *FakeClock) schedule(d, period time.Duration, f func()) *fakeTimer {
+	t := &fakeTimer{
+		clock:  c,
+		f:      f,
+		period: period,
+	}
+
+	if f == nil {
+		t.c = make(chan time.Time, 1)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d <= 0 {
+		t.fire(c.now)
+		return t
+	}
+
+	t.at = c.now.Add(d)
+	c.addWaiter(t)
+
+	return t
+}
+
+// caller must hold mu
+func (c *FakeClock) addWaiter(t *fakeTimer) {
+	c.waiters = append(c.waiters, t)
+	c.sortWaiters()
+	c.cond.Broadcast()
+}
+
+// removes the given waiter; returns false if it was not registered. Caller
+// must hold mu
+func (c *FakeClock) removeWaiter(t *fakeTimer) bool {
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// caller must hold mu
+func (c *FakeClock) sortWaiters() {
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].at.Before(c.waiters[j].at)
+	})
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	c      chan time.Time
+	f      func()
+	at     time.Time
+	period time.Duration
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.removeWaiter(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.removeWaiter(t)
+
+	if d <= 0 {
+		t.fire(t.clock.now)
+		return active
+	}
+
+	t.at = t.clock.now.Add(d)
+	t.clock.addWaiter(t)
+
+	return active
+}
+
+// delivers the given time (without blocking) or calls the function
+func (t *fakeTimer) fire(now time.Time) {
+	if t.f != nil {
+		go t.f()
+		return
+	}
+
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
@@ -0,0 +1,103 @@
+package fakes
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFakeClock(t *testing.T) {
+	RegisterTestingT(t)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Should only move when advanced", func(t *testing.T) {
+		c := NewFakeClock(start)
+		Expect(c.Now()).To(Equal(start))
+
+		c.Advance(time.Minute)
+		Expect(c.Now()).To(Equal(start.Add(time.Minute)))
+		Expect(c.Since(start)).To(Equal(time.Minute))
+	})
+
+	t.Run("Should fire timers once they are due", func(t *testing.T) {
+		c := NewFakeClock(start)
+		timer := c.NewTimer(time.Second)
+		after := c.After(2 * time.Second)
+		Expect(c.Waiters()).To(Equal(2))
+
+		c.Advance(999 * time.Millisecond)
+		Expect(timer.C()).ToNot(Receive())
+
+		c.Advance(time.Millisecond)
+		Expect(timer.C()).To(Receive(Equal(start.Add(time.Second))))
+		Expect(after).ToNot(Receive())
+		Expect(timer.Stop()).To(BeFalse())
+
+		c.Advance(time.Hour)
+		Expect(after).To(Receive(Equal(start.Add(2 * time.Second))))
+		Expect(c.Waiters()).To(BeZero())
+	})
+
+	t.Run("Should stop and reset timers", func(t *testing.T) {
+		c := NewFakeClock(start)
+		timer := c.NewTimer(time.Second)
+
+		Expect(timer.Stop()).To(BeTrue())
+		c.Advance(time.Second)
+		Expect(timer.C()).ToNot(Receive())
+
+		Expect(timer.Reset(time.Second)).To(BeFalse())
+		Expect(timer.Reset(2 * time.Second)).To(BeTrue())
+		c.Advance(time.Second)
+		Expect(timer.C()).ToNot(Receive())
+		c.Advance(time.Second)
+		Expect(timer.C()).To(Receive(Equal(start.Add(3 * time.Second))))
+	})
+
+	t.Run("Should tick until stopped", func(t *testing.T) {
+		c := NewFakeClock(start)
+		ticker := c.NewTicker(time.Second)
+
+		c.Advance(time.Second)
+		Expect(ticker.C()).To(Receive(Equal(start.Add(time.Second))))
+
+		// ticks are dropped if the receiver falls behind, like with time.Ticker
+		c.Advance(3 * time.Second)
+		Expect(ticker.C()).To(Receive(Equal(start.Add(2 * time.Second))))
+		Expect(ticker.C()).ToNot(Receive())
+
+		ticker.Stop()
+		c.Advance(time.Second)
+		Expect(ticker.C()).ToNot(Receive())
+		Expect(c.Waiters()).To(BeZero())
+	})
+
+	t.Run("Should call functions registered via AfterFunc", func(t *testing.T) {
+		c := NewFakeClock(start)
+		called := make(chan struct{})
+
+		timer := c.AfterFunc(time.Second, func() { close(called) })
+		Expect(timer.C()).To(BeNil())
+
+		c.Advance(time.Second)
+		Eventually(called).Should(BeClosed())
+	})
+
+	t.Run("Should block until enough waiters are registered", func(t *testing.T) {
+		c := NewFakeClock(start)
+		done := make(chan struct{})
+
+		go func() {
+			c.BlockUntil(2)
+			close(done)
+		}()
+
+		c.NewTimer(time.Second)
+		Consistently(done).ShouldNot(BeClosed())
+
+		c.NewTimer(time.Second)
+		Eventually(done).Should(BeClosed())
+	})
+}
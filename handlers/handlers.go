@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/InVisionApp/go-health/v2"
@@ -14,22 +16,48 @@ type jsonStatus struct {
 	Status  string `json:"status"`
 }
 
+// AuthFunc is used by handlers that trigger side effects to authenticate the
+// incoming request; it should return true if the request is allowed.
+type AuthFunc func(r *http.Request) bool
+
 type mutexMap struct {
 	sync.Mutex
 	data map[string]interface{}
 }
 
-// NewBasicHandlerFunc will return an `http.HandlerFunc` that will write `ok`
-// string + `http.StatusOK` to `rw`` if `h.Failed()` returns `false`;
-// returns `error` + `http.StatusInternalServerError` if `h.Failed()` returns `true`.
+// NewBasicHandlerFunc will return an `http.HandlerFunc` that will write the
+// overall status (`ok` or `degraded`) + `http.StatusOK` to `rw`` if `h.Failed()`
+// returns `false`; returns `failed` + `http.StatusInternalServerError` if
+// `h.Failed()` returns `true`.
 func NewBasicHandlerFunc(h health.IHealth) http.HandlerFunc {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		status := http.StatusOK
-		body := "ok"
+		body := h.Status()
+
+		if body == health.StatusFailed {
+			status = http.StatusInternalServerError
+		}
+
+		rw.WriteHeader(status)
+		rw.Write([]byte(body))
+	})
+}
+
+// NewGroupHandlerFunc will return an `http.HandlerFunc` that behaves like the
+// handler returned by `NewBasicHandlerFunc`, but only takes the checks that are
+// members of `group` into account (ie. uses `h.FailedFor(group)`).
+func NewGroupHandlerFunc(h health.IHealth, group string) http.HandlerFunc {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		states, failed, _ := h.StateFor(group)
+
+		status := http.StatusOK
+		body := health.StatusOK
 
-		if h.Failed() {
+		if failed {
 			status = http.StatusInternalServerError
-			body = "failed"
+			body = health.StatusFailed
+		} else if degraded(states) {
+			body = health.StatusDegraded
 		}
 
 		rw.WriteHeader(status)
@@ -37,10 +65,29 @@ func NewBasicHandlerFunc(h health.IHealth) http.HandlerFunc {
 	})
 }
 
+// NewLivenessHandlerFunc will return an `http.HandlerFunc` for the checks in
+// the `health.GroupLiveness` group; intended to be served under `/livez`.
+func NewLivenessHandlerFunc(h health.IHealth) http.HandlerFunc {
+	return NewGroupHandlerFunc(h, health.GroupLiveness)
+}
+
+// NewReadinessHandlerFunc will return an `http.HandlerFunc` for the checks in
+// the `health.GroupReadiness` group; intended to be served under `/readyz`.
+func NewReadinessHandlerFunc(h health.IHealth) http.HandlerFunc {
+	return NewGroupHandlerFunc(h, health.GroupReadiness)
+}
+
+// NewStartupHandlerFunc will return an `http.HandlerFunc` for the checks in
+// the `health.GroupStartup` group; intended to be served under `/startupz`.
+func NewStartupHandlerFunc(h health.IHealth) http.HandlerFunc {
+	return NewGroupHandlerFunc(h, health.GroupStartup)
+}
+
 // NewJSONHandlerFunc will return an `http.HandlerFunc` that will marshal and
 // write the contents of `h.StateMapInterface()` to `rw` and set status code to
 //  `http.StatusOK` if `h.Failed()` is `false` OR set status code to
-// `http.StatusInternalServerError` if `h.Failed` is `true`.
+// `http.StatusInternalServerError` if `h.Failed` is `true`. If no check has
+// failed, but one or more checks report a warning, the body status is `degraded`.
 // It also accepts a set of optional custom fields to be added to the final JSON body
 func NewJSONHandlerFunc(h health.IHealth, custom map[string]interface{}) http.HandlerFunc {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -50,7 +97,7 @@ func NewJSONHandlerFunc(h health.IHealth, custom map[string]interface{}) http.Ha
 			return
 		}
 
-		msg := "ok"
+		msg := health.StatusOK
 		statusCode := http.StatusOK
 
 		// There may be an _initial_ delay in display healthcheck data as the
@@ -61,15 +108,17 @@ func NewJSONHandlerFunc(h health.IHealth, custom map[string]interface{}) http.Ha
 		}
 
 		if failed {
-			msg = "failed"
+			msg = health.StatusFailed
 			statusCode = http.StatusInternalServerError
+		} else if degraded(states) {
+			msg = health.StatusDegraded
 		}
 
 		fullBody := mutexMap{}
 		fullBody.Lock()
 		fullBody.data = map[string]interface{}{
 			"status":  msg,
-			"details": states,
+			"details": redactStates(r, states),
 		}
 
 		for k, v := range custom {
@@ -89,6 +138,320 @@ func NewJSONHandlerFunc(h health.IHealth, custom map[string]interface{}) http.Ha
 	})
 }
 
+// NewHistoryHandlerFunc will return an `http.HandlerFunc` that will marshal and
+// write the recorded results of every check (see `h.History()`) to `rw`, keyed
+// by the name of the check. The `check` query parameter can be used to limit
+// the output to a single check; `http.StatusNotFound` is returned if that check
+// does not exist.
+func NewHistoryHandlerFunc(h health.IHealth) http.HandlerFunc {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		histories := make(map[string][]health.HistoryEntry, 0)
+
+		states, _, err := h.State()
+		if err != nil {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to fetch states: %v", err), http.StatusOK)
+			return
+		}
+
+		if name := r.URL.Query().Get("check"); name != "" {
+			entries, err := h.History(name)
+			if err != nil {
+				writeJSONStatus(rw, "error", fmt.Sprintf("Unable to fetch history: %v", err), http.StatusNotFound)
+				return
+			}
+
+			histories[name] = redactHistory(r, states[name].Sensitive, entries)
+		} else {
+			for name, s := range states {
+				// the check may have been removed in the meantime
+				if entries, err := h.History(name); err == nil {
+					histories[name] = redactHistory(r, s.Sensitive, entries)
+				}
+			}
+		}
+
+		data, err := json.Marshal(histories)
+		if err != nil {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Failed to marshal history data: %v", err), http.StatusOK)
+			return
+		}
+
+		writeJSONResponse(rw, http.StatusOK, data)
+	})
+}
+
+// NewRunNowHandlerFunc will return an `http.HandlerFunc` that will run the
+// checks immediately (see `h.RunAll()`) and write their fresh states to `rw`,
+// keyed by the name of the check. The `check` query parameter can be used to
+// only run a single check (see `h.RunNow()`). Only `POST` requests are
+// accepted; if `auth` is not nil, requests it rejects are answered with
+// `http.StatusUnauthorized`.
+func NewRunNowHandlerFunc(h health.IHealth, auth AuthFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			rw.Header().Set("Allow", http.MethodPost)
+			writeJSONStatus(rw, "error", "Only POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if auth != nil && !auth(r) {
+			writeJSONStatus(rw, "error", "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var (
+			states map[string]health.State
+			err    error
+		)
+
+		if name := r.URL.Query().Get("check"); name != "" {
+			var state health.State
+
+			state, err = h.RunNow(name)
+			states = map[string]health.State{name: state}
+		} else {
+			states, err = h.RunAll(r.Context())
+		}
+
+		switch err {
+		case nil:
+		case health.ErrCheckNotFound:
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to run check: %v", err), http.StatusNotFound)
+			return
+		case health.ErrNotRunning:
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to run check: %v", err), http.StatusServiceUnavailable)
+			return
+		default:
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to run checks: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		data, err := json.Marshal(redactStates(r, states))
+		if err != nil {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Failed to marshal state data: %v", err), http.StatusOK)
+			return
+		}
+
+		writeJSONResponse(rw, http.StatusOK, data)
+	})
+}
+
+// statusPending is reported by `NewCheckHandlerFunc` for checks that have been
+// added, but have not reported a result yet
+const statusPending = "pending"
+
+// NewCheckHandlerFunc will return an `http.HandlerFunc` that serves the checks
+// under `prefix` (ie. `/health/`): requests for `<prefix><name>` are answered
+// with the state of the named check (`http.StatusNotFound` if it does not
+// exist), requests for `prefix` itself with the checks selected by the
+// following query parameters (which may be repeated or comma separated):
+//
+//   - `include`: only the named checks (`http.StatusNotFound` if one does not exist)
+//   - `exclude`: all checks except the named ones
+//   - `group`: only the checks that are members of the group
+//
+// The status code is `http.StatusInternalServerError` if a single check is not
+// passing (regardless of `Fatal`), or if the selected checks have failed as
+// determined by `h.Failed()` (`h.FailedFor(group)` with `group`; or the
+// configured `health.Aggregator` with `include`/`exclude`, see
+// `health.Health.FailedStates()`). A check that has been
+// added, but has not reported a result yet (ie. during its `InitialDelay`), is
+// `pending` with `http.StatusServiceUnavailable`. The body is the status
+// (like `NewBasicHandlerFunc`), unless the `verbose` query parameter is set, in
+// which case the states are written as JSON (like `NewJSONHandlerFunc`).
+func NewCheckHandlerFunc(h health.IHealth, prefix string) http.HandlerFunc {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to find check at '%s'", r.URL.Path), http.StatusNotFound)
+			return
+		}
+
+		query := r.URL.Query()
+		_, verbose := query["verbose"]
+
+		if name := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"); name != "" {
+			writeCheckState(rw, r, h, name, verbose)
+			return
+		}
+
+		allStates, failed, err := h.State()
+		if err != nil {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to fetch states: %v", err), http.StatusOK)
+			return
+		}
+
+		states := allStates
+		group := query.Get("group")
+
+		if group != "" {
+			states, failed, err = h.StateFor(group)
+			if err != nil {
+				writeJSONStatus(rw, "error", fmt.Sprintf("Unable to fetch states: %v", err), http.StatusOK)
+				return
+			}
+		}
+
+		var pending []string
+
+		include := queryList(query, "include")
+		exclude := queryList(query, "exclude")
+
+		if len(include) > 0 || len(exclude) > 0 {
+			selected := make(map[string]health.State, 0)
+
+			for _, name := range include {
+				if s, ok := states[name]; ok {
+					selected[name] = s
+					continue
+				}
+
+				if _, ok := allStates[name]; ok {
+					writeJSONStatus(rw, "error", fmt.Sprintf("Check '%s' is not a member of group '%s'", name, group), http.StatusNotFound)
+					return
+				}
+
+				if !checkAdded(h, allStates, name) {
+					writeJSONStatus(rw, "error", fmt.Sprintf("Unable to find check '%s'", name), http.StatusNotFound)
+					return
+				}
+
+				pending = append(pending, name)
+			}
+
+			if len(include) == 0 {
+				for name, s := range states {
+					selected[name] = s
+				}
+			}
+
+			for _, name := range exclude {
+				delete(selected, name)
+			}
+
+			states = selected
+			failed = h.FailedStates(states)
+		}
+
+		status := health.StatusOK
+		statusCode := http.StatusOK
+
+		if len(pending) > 0 {
+			status = statusPending
+			statusCode = http.StatusServiceUnavailable
+		} else if failed {
+			status = health.StatusFailed
+			statusCode = http.StatusInternalServerError
+		} else if degraded(states) {
+			status = health.StatusDegraded
+		}
+
+		if !verbose {
+			rw.WriteHeader(statusCode)
+			rw.Write([]byte(status))
+			return
+		}
+
+		data, err := json.Marshal(map[string]interface{}{
+			"status":  status,
+			"details": redactStates(r, states),
+		})
+		if err != nil {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Failed to marshal state data: %v", err), http.StatusOK)
+			return
+		}
+
+		writeJSONResponse(rw, statusCode, data)
+	})
+}
+
+// writes the state of the named check for `NewCheckHandlerFunc`
+func writeCheckState(rw http.ResponseWriter, r *http.Request, h health.IHealth, name string, verbose bool) {
+	states, _, err := h.State()
+	if err != nil {
+		writeJSONStatus(rw, "error", fmt.Sprintf("Unable to fetch states: %v", err), http.StatusOK)
+		return
+	}
+
+	s, ok := states[name]
+	if !ok {
+		if !checkAdded(h, states, name) {
+			writeJSONStatus(rw, "error", fmt.Sprintf("Unable to find check '%s'", name), http.StatusNotFound)
+			return
+		}
+
+		if !verbose {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			rw.Write([]byte(statusPending))
+			return
+		}
+
+		writeJSONStatus(rw, statusPending, fmt.Sprintf("Check '%s' has not reported yet", name), http.StatusServiceUnavailable)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if s.Status != health.StatusOK && s.Status != health.StatusWarn {
+		statusCode = http.StatusInternalServerError
+	}
+
+	if !verbose {
+		rw.WriteHeader(statusCode)
+		rw.Write([]byte(s.Status))
+		return
+	}
+
+	data, err := json.Marshal(redactState(r, s))
+	if err != nil {
+		writeJSONStatus(rw, "error", fmt.Sprintf("Failed to marshal state data: %v", err), http.StatusOK)
+		return
+	}
+
+	writeJSONResponse(rw, statusCode, data)
+}
+
+// indicates whether any of the given states is reporting a warning (ie. the
+// states are `degraded`, unless they have failed)
+func degraded(states map[string]health.State) bool {
+	for _, s := range states {
+		if s.Status == health.StatusWarn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indicates whether the named check has been added to `h`
+func checkAdded(h health.IHealth, states map[string]health.State, name string) bool {
+	if _, ok := states[name]; ok {
+		return true
+	}
+
+	for _, c := range h.Checks() {
+		if c == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns the values of the given (repeated and/or comma separated) query
+// parameter
+func queryList(query url.Values, key string) []string {
+	var list []string
+
+	for _, v := range query[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
 func writeJSONStatus(rw http.ResponseWriter, status, message string, statusCode int) {
 	jsonData, _ := json.Marshal(&jsonStatus{
 		Message: message,
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2"
+	"github.com/InVisionApp/go-health/v2/fakes"
+)
+
+var testCheckInterval = time.Duration(10) * time.Millisecond
+
+// starts a health instance running the given checks on a fake clock and waits
+// for them to report (unless their first run is delayed)
+func setupHealth(cfgs []*health.Config) *health.Health {
+	clk := fakes.NewFakeClock(time.Now())
+
+	h := health.New()
+	h.DisableLogging()
+	h.Clock = clk
+
+	Expect(h.AddChecks(cfgs)).To(Succeed())
+	Expect(h.Start()).To(Succeed())
+
+	// a delayed check only arms its initial delay; every other check arms the
+	// timer of its next run (and its staleness timer, unless disabled) once
+	// its first run has been recorded
+	waiters := 0
+
+	for _, c := range cfgs {
+		waiters++
+
+		if c.InitialDelay <= 0 && c.MaxAge >= 0 {
+			waiters++
+		}
+	}
+
+	Eventually(clk.Waiters).Should(BeNumerically(">=", waiters))
+
+	return h
+}
+
+func newTestCheck(name string, fatal bool, err error) *health.Config {
+	checker := &fakes.FakeICheckable{}
+	checker.StatusReturns(nil, err)
+
+	return &health.Config{
+		Name:     name,
+		Checker:  checker,
+		Interval: testCheckInterval,
+		Fatal:    fatal,
+	}
+}
+
+func TestNewBasicHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return ok if no check has failed", func(t *testing.T) {
+		h := setupHealth([]*health.Config{newTestCheck("foo", true, nil)})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewBasicHandlerFunc(h)(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok"))
+	})
+
+	t.Run("Should return degraded with a 200 if a check reports a warning", func(t *testing.T) {
+		h := setupHealth([]*health.Config{
+			newTestCheck("foo", true, &health.Warning{Err: errors.New("slow")}),
+		})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewBasicHandlerFunc(h)(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("degraded"))
+	})
+
+	t.Run("Should return failed with a 500 if a fatal check has failed", func(t *testing.T) {
+		h := setupHealth([]*health.Config{newTestCheck("foo", true, errors.New("broken"))})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewBasicHandlerFunc(h)(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(Equal("failed"))
+	})
+}
+
+func TestNewGroupHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	db := newTestCheck("db", true, errors.New("broken"))
+	db.Groups = []string{health.GroupReadiness}
+
+	loop := newTestCheck("loop", true, nil)
+	loop.Groups = []string{health.GroupLiveness, health.GroupStartup}
+
+	h := setupHealth([]*health.Config{db, loop})
+	defer h.Stop()
+
+	t.Run("Should return failed if a check in the group has failed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewReadinessHandlerFunc(h)(rec, httptest.NewRequest("GET", "/readyz", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(Equal("failed"))
+	})
+
+	t.Run("Should return ok if no check in the group has failed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewLivenessHandlerFunc(h)(rec, httptest.NewRequest("GET", "/livez", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok"))
+	})
+
+	t.Run("Should return ok once all startup checks have passed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewStartupHandlerFunc(h)(rec, httptest.NewRequest("GET", "/startupz", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok"))
+	})
+}
+
+func TestNewJSONHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return the states and custom fields", func(t *testing.T) {
+		h := setupHealth([]*health.Config{newTestCheck("foo", true, nil)})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewJSONHandlerFunc(h, map[string]interface{}{"version": "1.0.0"})(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		body := map[string]interface{}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(body["status"]).To(Equal("ok"))
+		Expect(body["version"]).To(Equal("1.0.0"))
+		Expect(body["details"]).To(HaveKey("foo"))
+	})
+
+	t.Run("Should return degraded with a 200 if a check reports a warning", func(t *testing.T) {
+		h := setupHealth([]*health.Config{
+			newTestCheck("foo", true, nil),
+			newTestCheck("bar", false, &health.Warning{Err: errors.New("slow")}),
+		})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewJSONHandlerFunc(h, nil)(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		body := map[string]interface{}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body["status"]).To(Equal("degraded"))
+	})
+
+	t.Run("Should return failed with a 500 if a fatal check has failed", func(t *testing.T) {
+		h := setupHealth([]*health.Config{newTestCheck("foo", true, errors.New("broken"))})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewJSONHandlerFunc(h, nil)(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		body := map[string]interface{}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(body["status"]).To(Equal("failed"))
+	})
+
+	t.Run("Should show the root cause of skipped checks", func(t *testing.T) {
+		dependent := newTestCheck("bar", true, nil)
+		dependent.DependsOn = []string{"foo"}
+
+		h := setupHealth([]*health.Config{newTestCheck("foo", false, errors.New("broken")), dependent})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewJSONHandlerFunc(h, nil)(rec, httptest.NewRequest("GET", "/healthcheck", nil))
+
+		body := struct {
+			Status  string                  `json:"status"`
+			Details map[string]health.State `json:"details"`
+		}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(body.Status).To(Equal("failed"))
+		Expect(body.Details["bar"].Status).To(Equal(health.StatusSkipped))
+		Expect(body.Details["bar"].RootCause).To(Equal("foo"))
+	})
+}
+
+func TestNewHistoryHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	foo := newTestCheck("foo", true, errors.New("broken"))
+	foo.HistorySize = 5
+
+	h := setupHealth([]*health.Config{foo, newTestCheck("bar", false, nil)})
+	defer h.Stop()
+
+	t.Run("Should return the history of all checks", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewHistoryHandlerFunc(h)(rec, httptest.NewRequest("GET", "/history", nil))
+
+		body := map[string][]health.HistoryEntry{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body).To(HaveKey("foo"))
+		Expect(body).To(HaveKey("bar"))
+		Expect(body["foo"]).ToNot(BeEmpty())
+		Expect(body["foo"][0].Status).To(Equal(health.StatusFailed))
+		Expect(body["foo"][0].Err).To(Equal("broken"))
+		Expect(body["bar"]).To(BeEmpty())
+	})
+
+	t.Run("Should only return the history of the requested check", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewHistoryHandlerFunc(h)(rec, httptest.NewRequest("GET", "/history?check=foo", nil))
+
+		body := map[string][]health.HistoryEntry{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body).To(HaveLen(1))
+		Expect(body).To(HaveKey("foo"))
+	})
+
+	t.Run("Should return a 404 for an unknown check", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewHistoryHandlerFunc(h)(rec, httptest.NewRequest("GET", "/history?check=baz", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+}
+
+func TestNewRunNowHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	h := setupHealth([]*health.Config{
+		newTestCheck("foo", true, errors.New("broken")),
+		newTestCheck("bar", false, nil),
+	})
+	defer h.Stop()
+
+	auth := func(r *http.Request) bool {
+		return r.Header.Get("X-Token") == "secret"
+	}
+
+	newRequest := func(method, target string) *http.Request {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("X-Token", "secret")
+
+		return req
+	}
+
+	t.Run("Should run all checks and return their states", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, auth)(rec, newRequest("POST", "/run"))
+
+		body := map[string]health.State{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body).To(HaveLen(2))
+		Expect(body["foo"].Status).To(Equal(health.StatusFailed))
+		Expect(body["bar"].Status).To(Equal(health.StatusOK))
+	})
+
+	t.Run("Should only run the requested check", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, auth)(rec, newRequest("POST", "/run?check=bar"))
+
+		body := map[string]health.State{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body).To(HaveLen(1))
+		Expect(body).To(HaveKey("bar"))
+	})
+
+	t.Run("Should return a 404 for an unknown check", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, auth)(rec, newRequest("POST", "/run?check=baz"))
+
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	t.Run("Should only accept POST requests", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, auth)(rec, newRequest("GET", "/run"))
+
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	t.Run("Should reject unauthenticated requests", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, auth)(rec, httptest.NewRequest("POST", "/run", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	t.Run("Should allow all requests without an auth func", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, nil)(rec, httptest.NewRequest("POST", "/run", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+}
+
+func TestNewCheckHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	liveness := newTestCheck("live", true, nil)
+	liveness.Groups = []string{health.GroupLiveness}
+
+	h := setupHealth([]*health.Config{
+		newTestCheck("foo", true, errors.New("broken")),
+		newTestCheck("bar", false, &health.Warning{Err: errors.New("slow")}),
+		newTestCheck("baz", false, errors.New("broken")),
+		liveness,
+	})
+	defer h.Stop()
+
+	handler := NewCheckHandlerFunc(h, "/health/")
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", target, nil))
+
+		return rec
+	}
+
+	t.Run("Should return the status of a single check", func(t *testing.T) {
+		rec := serve("/health/live")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok"))
+
+		rec = serve("/health/bar")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("warn"))
+
+		// non-fatal, but asked for explicitly
+		rec = serve("/health/baz")
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(Equal("failed"))
+	})
+
+	t.Run("Should return the state of a single check if verbose", func(t *testing.T) {
+		rec := serve("/health/foo?verbose")
+
+		body := health.State{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(body.Name).To(Equal("foo"))
+		Expect(body.Err).To(Equal("broken"))
+	})
+
+	t.Run("Should return a 404 for unknown checks", func(t *testing.T) {
+		Expect(serve("/health/unknown").Code).To(Equal(http.StatusNotFound))
+		Expect(serve("/health/?include=foo,unknown").Code).To(Equal(http.StatusNotFound))
+		Expect(serve("/other").Code).To(Equal(http.StatusNotFound))
+	})
+
+	t.Run("Should return the overall status without filters", func(t *testing.T) {
+		rec := serve("/health/")
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(Equal("failed"))
+	})
+
+	t.Run("Should apply the include, exclude and group filters", func(t *testing.T) {
+		rec := serve("/health/?exclude=foo")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("degraded"))
+
+		rec = serve("/health/?include=live&include=baz")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok"))
+
+		rec = serve("/health/?include=live,foo")
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(Equal("failed"))
+
+		rec = serve("/health/?group=" + health.GroupLiveness)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok"))
+	})
+
+	t.Run("Should use the configured aggregator for the include and exclude filters", func(t *testing.T) {
+		h := setupHealth([]*health.Config{
+			newTestCheck("foo", true, errors.New("broken")),
+			newTestCheck("bar", true, nil),
+			newTestCheck("baz", true, nil),
+		})
+		defer h.Stop()
+
+		h.Aggregator = health.AllFailAggregator{}
+		handler := NewCheckHandlerFunc(h, "/health/")
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/?include=foo,bar", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/?exclude=bar,baz", nil))
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	t.Run("Should return pending for checks that have not reported yet", func(t *testing.T) {
+		delayed := newTestCheck("delayed", true, nil)
+		delayed.InitialDelay = time.Hour
+
+		h := setupHealth([]*health.Config{newTestCheck("foo", true, nil), delayed})
+		defer h.Stop()
+
+		handler := NewCheckHandlerFunc(h, "/health/")
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/delayed", nil))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rec.Body.String()).To(Equal("pending"))
+
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/delayed?verbose", nil))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rec.Body.String()).To(ContainSubstring(`"status":"pending"`))
+
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/?include=foo,delayed", nil))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rec.Body.String()).To(Equal("pending"))
+
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/?include=foo", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/health/?include=foo&group="+health.GroupLiveness, nil))
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	t.Run("Should return the selected states if verbose", func(t *testing.T) {
+		rec := serve("/health/?exclude=foo&verbose")
+
+		body := struct {
+			Status  string                  `json:"status"`
+			Details map[string]health.State `json:"details"`
+		}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body.Status).To(Equal("degraded"))
+		Expect(body.Details).To(HaveLen(3))
+		Expect(body.Details).ToNot(HaveKey("foo"))
+	})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/InVisionApp/go-health/v2"
+)
+
+const healthJSONContentType = "application/health+json"
+
+// Statuses of the `application/health+json` format
+const (
+	HealthJSONPass = "pass"
+	HealthJSONWarn = "warn"
+	HealthJSONFail = "fail"
+)
+
+// HealthJSONOptions holds the service level fields of the responses written
+// by the handler returned by `NewHealthJSONHandlerFunc`; all of them are
+// optional.
+type HealthJSONOptions struct {
+	// Version is the public version of the service
+	Version string
+
+	// ReleaseID is the "release version" of the service
+	ReleaseID string
+
+	// ServiceID is the unique identifier of the service
+	ServiceID string
+
+	// Description is a human-friendly description of the service
+	Description string
+
+	// Notes are additional human-readable notes about the service
+	Notes []string
+}
+
+type healthJSONResponse struct {
+	Status      string                       `json:"status"`
+	Version     string                       `json:"version,omitempty"`
+	ReleaseID   string                       `json:"releaseId,omitempty"`
+	ServiceID   string                       `json:"serviceId,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Notes       []string                     `json:"notes,omitempty"`
+	Output      string                       `json:"output,omitempty"`
+	Checks      map[string][]healthJSONCheck `json:"checks,omitempty"`
+}
+
+type healthJSONCheck struct {
+	ComponentID   string    `json:"componentId"`
+	Status        string    `json:"status"`
+	ObservedValue float64   `json:"observedValue"`
+	ObservedUnit  string    `json:"observedUnit"`
+	Time          time.Time `json:"time"`
+	Output        string    `json:"output,omitempty"`
+}
+
+// NewHealthJSONHandlerFunc will return an `http.HandlerFunc` that will write
+// the state of every check to `rw` in the `application/health+json` format
+// (see https://tools.ietf.org/html/draft-inadarei-api-health-check). Every
+// check is reported under the `<name>:responseTime` key, with the duration of
+// its last run (in milliseconds) as the observed value. The overall status is
+// `fail` (with `http.StatusServiceUnavailable`) if `h.Failed()` is `true`,
+// `warn` if the health is degraded and `pass` otherwise; `opts` may be nil.
+func NewHealthJSONHandlerFunc(h health.IHealth, opts *HealthJSONOptions) http.HandlerFunc {
+	if opts == nil {
+		opts = &HealthJSONOptions{}
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		resp := &healthJSONResponse{
+			Status:      HealthJSONPass,
+			Version:     opts.Version,
+			ReleaseID:   opts.ReleaseID,
+			ServiceID:   opts.ServiceID,
+			Description: opts.Description,
+			Notes:       opts.Notes,
+		}
+
+		states, failed, err := h.State()
+		if err != nil {
+			resp.Status = HealthJSONFail
+			resp.Output = fmt.Sprintf("Unable to fetch states: %v", err)
+
+			writeHealthJSONResponse(rw, resp)
+			return
+		}
+
+		// There may be an _initial_ delay in display healthcheck data as the
+		// healthchecks will only begin firing at "initialTime + checkIntervalTime"
+		if len(states) == 0 {
+			resp.Notes = append(append([]string{}, resp.Notes...), "Healthcheck spinning up")
+
+			writeHealthJSONResponse(rw, resp)
+			return
+		}
+
+		if failed {
+			resp.Status = HealthJSONFail
+		} else if degraded(states) {
+			resp.Status = HealthJSONWarn
+		}
+
+		resp.Checks = make(map[string][]healthJSONCheck, len(states))
+
+		for name, s := range states {
+			check := healthJSONCheck{
+				ComponentID:   name,
+				Status:        healthJSONStatus(s.Status),
+				ObservedValue: float64(s.Duration) / float64(time.Millisecond),
+				ObservedUnit:  "ms",
+				Time:          s.CheckTime,
+			}
+
+			// the output should be omitted for passing checks
+			if check.Status != HealthJSONPass {
+				check.Output = redactState(r, s).Err
+			}
+
+			resp.Checks[name+":responseTime"] = []healthJSONCheck{check}
+		}
+
+		writeHealthJSONResponse(rw, resp)
+	})
+}
+
+// maps the status of a check to the `application/health+json` status
+func healthJSONStatus(status string) string {
+	switch status {
+	case health.StatusOK:
+		return HealthJSONPass
+	case health.StatusWarn:
+		return HealthJSONWarn
+	default:
+		return HealthJSONFail
+	}
+}
+
+func writeHealthJSONResponse(rw http.ResponseWriter, resp *healthJSONResponse) {
+	statusCode := http.StatusOK
+	if resp.Status == HealthJSONFail {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		writeJSONStatus(rw, "error", fmt.Sprintf("Failed to marshal state data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", healthJSONContentType)
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	rw.WriteHeader(statusCode)
+	rw.Write(data)
+}
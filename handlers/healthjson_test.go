@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2"
+)
+
+func TestNewHealthJSONHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return pass with the service fields and checks", func(t *testing.T) {
+		h := setupHealth([]*health.Config{newTestCheck("foo", true, nil)})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewHealthJSONHandlerFunc(h, &HealthJSONOptions{
+			Version:   "1",
+			ReleaseID: "1.0.0",
+			ServiceID: "f03e522f-1f44-4062-9b55-9587f91c9c41",
+		})(rec, httptest.NewRequest("GET", "/health", nil))
+
+		body := healthJSONResponse{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/health+json"))
+		Expect(body.Status).To(Equal(HealthJSONPass))
+		Expect(body.Version).To(Equal("1"))
+		Expect(body.ReleaseID).To(Equal("1.0.0"))
+		Expect(body.ServiceID).To(Equal("f03e522f-1f44-4062-9b55-9587f91c9c41"))
+
+		Expect(body.Checks).To(HaveKey("foo:responseTime"))
+		check := body.Checks["foo:responseTime"][0]
+		Expect(check.ComponentID).To(Equal("foo"))
+		Expect(check.Status).To(Equal(HealthJSONPass))
+		Expect(check.ObservedUnit).To(Equal("ms"))
+		Expect(check.Time.IsZero()).To(BeFalse())
+		Expect(check.Output).To(BeEmpty())
+	})
+
+	t.Run("Should return warn with a 200 if a check reports a warning", func(t *testing.T) {
+		h := setupHealth([]*health.Config{
+			newTestCheck("foo", true, nil),
+			newTestCheck("bar", false, &health.Warning{Err: errors.New("slow")}),
+		})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewHealthJSONHandlerFunc(h, nil)(rec, httptest.NewRequest("GET", "/health", nil))
+
+		body := healthJSONResponse{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body.Status).To(Equal(HealthJSONWarn))
+		Expect(body.Checks["bar:responseTime"][0].Status).To(Equal(HealthJSONWarn))
+		Expect(body.Checks["bar:responseTime"][0].Output).To(Equal("slow"))
+	})
+
+	t.Run("Should return fail with a 503 if a fatal check has failed", func(t *testing.T) {
+		h := setupHealth([]*health.Config{
+			newTestCheck("foo", true, errors.New("broken")),
+			newTestCheck("bar", false, nil),
+		})
+		defer h.Stop()
+
+		rec := httptest.NewRecorder()
+		NewHealthJSONHandlerFunc(h, nil)(rec, httptest.NewRequest("GET", "/health", nil))
+
+		body := healthJSONResponse{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(body.Status).To(Equal(HealthJSONFail))
+		Expect(body.Checks["foo:responseTime"][0].Status).To(Equal(HealthJSONFail))
+		Expect(body.Checks["foo:responseTime"][0].Output).To(Equal("broken"))
+		Expect(body.Checks["bar:responseTime"][0].Status).To(Equal(HealthJSONPass))
+	})
+
+	t.Run("Should return pass while spinning up", func(t *testing.T) {
+		h := health.New()
+		h.DisableLogging()
+
+		rec := httptest.NewRecorder()
+		NewHealthJSONHandlerFunc(h, &HealthJSONOptions{Notes: []string{"note"}})(rec, httptest.NewRequest("GET", "/health", nil))
+
+		body := healthJSONResponse{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(body.Status).To(Equal(HealthJSONPass))
+		Expect(body.Notes).To(Equal([]string{"note", "Healthcheck spinning up"}))
+		Expect(body.Checks).To(BeEmpty())
+	})
+}
+
+func TestHealthJSONStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(healthJSONStatus(health.StatusOK)).To(Equal(HealthJSONPass))
+	Expect(healthJSONStatus(health.StatusWarn)).To(Equal(HealthJSONWarn))
+	Expect(healthJSONStatus(health.StatusFailed)).To(Equal(HealthJSONFail))
+	Expect(healthJSONStatus(health.StatusStale)).To(Equal(HealthJSONFail))
+	Expect(healthJSONStatus(health.StatusSkipped)).To(Equal(HealthJSONFail))
+}
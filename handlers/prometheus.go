@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/InVisionApp/go-health/v2"
+)
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// NewPrometheusHandlerFunc will return an `http.HandlerFunc` that will write
+// the state of every check to `rw` in the Prometheus text exposition format.
+// The following metrics are exposed (labeled by `check`):
+//
+//   - `health_up`: 1 if the overall health has not failed (see `h.Failed()`), 0 otherwise
+//   - `health_check_up`: 1 if the check is neither failed nor stale, 0 otherwise (also labeled by `fatal`)
+//   - `health_check_contiguous_failures`: number of contiguous failures of the check
+//   - `health_check_last_check_timestamp_seconds`: unix time of the last run of the check
+//   - `health_check_duration_seconds`: histogram of the run durations of the check
+//   - `health_check_failures_total`: total number of failed runs of the check
+func NewPrometheusHandlerFunc(h health.IHealth) http.HandlerFunc {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		states, failed, err := h.State()
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("Unable to fetch states: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		metrics := h.Metrics()
+
+		names := make([]string, 0, len(states))
+		for name := range states {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		buf := &bytes.Buffer{}
+
+		writeMetricHeader(buf, "health_up", "gauge", "Whether the overall health has not failed (1) or has failed (0).")
+		fmt.Fprintf(buf, "health_up %d\n", boolToInt(!failed))
+
+		writeMetricHeader(buf, "health_check_up", "gauge", "Whether the check is passing (1) or has failed, is stale or was skipped (0).")
+		for _, name := range names {
+			s := states[name]
+			fmt.Fprintf(buf, "health_check_up{check=\"%s\",fatal=\"%t\"} %d\n",
+				escapeLabelValue(name), s.Fatal, boolToInt(s.Status == health.StatusOK || s.Status == health.StatusWarn))
+		}
+
+		writeMetricHeader(buf, "health_check_contiguous_failures", "gauge", "Number of contiguous failures of the check.")
+		for _, name := range names {
+			fmt.Fprintf(buf, "health_check_contiguous_failures{check=\"%s\"} %d\n",
+				escapeLabelValue(name), states[name].ContiguousFailures)
+		}
+
+		writeMetricHeader(buf, "health_check_last_check_timestamp_seconds", "gauge", "Unix time of the last run of the check.")
+		for _, name := range names {
+			fmt.Fprintf(buf, "health_check_last_check_timestamp_seconds{check=\"%s\"} %s\n",
+				escapeLabelValue(name), formatFloat(float64(states[name].CheckTime.UnixNano())/1e9))
+		}
+
+		writeMetricHeader(buf, "health_check_duration_seconds", "histogram", "Duration of the runs of the check.")
+		for _, name := range names {
+			m, ok := metrics[name]
+			if !ok {
+				continue
+			}
+
+			label := escapeLabelValue(name)
+
+			for _, b := range m.DurationBuckets {
+				fmt.Fprintf(buf, "health_check_duration_seconds_bucket{check=\"%s\",le=\"%s\"} %d\n",
+					label, formatSeconds(b.UpperBound), b.Count)
+			}
+
+			fmt.Fprintf(buf, "health_check_duration_seconds_bucket{check=\"%s\",le=\"+Inf\"} %d\n", label, m.Runs)
+			fmt.Fprintf(buf, "health_check_duration_seconds_sum{check=\"%s\"} %s\n", label, formatSeconds(m.DurationSum))
+			fmt.Fprintf(buf, "health_check_duration_seconds_count{check=\"%s\"} %d\n", label, m.Runs)
+		}
+
+		writeMetricHeader(buf, "health_check_failures_total", "counter", "Total number of failed runs of the check.")
+		for _, name := range names {
+			if m, ok := metrics[name]; ok {
+				fmt.Fprintf(buf, "health_check_failures_total{check=\"%s\"} %d\n", escapeLabelValue(name), m.Failures)
+			}
+		}
+
+		rw.Header().Set("Content-Type", prometheusContentType)
+		rw.Header().Set("Content-Length", fmt.Sprintf("%d", buf.Len()))
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(buf.Bytes())
+	})
+}
+
+func writeMetricHeader(buf *bytes.Buffer, name, typ, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, typ)
+}
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+func formatSeconds(d time.Duration) string {
+	return formatFloat(d.Seconds())
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2"
+)
+
+func TestNewPrometheusHandlerFunc(t *testing.T) {
+	RegisterTestingT(t)
+
+	h := setupHealth([]*health.Config{
+		newTestCheck("foo", true, errors.New("broken")),
+		newTestCheck("bar", false, nil),
+	})
+	defer h.Stop()
+
+	rec := httptest.NewRecorder()
+	NewPrometheusHandlerFunc(h)(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+
+	t.Run("Should use the text exposition format", func(t *testing.T) {
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal(prometheusContentType))
+		Expect(body).To(ContainSubstring("# TYPE health_check_up gauge\n"))
+		Expect(body).To(ContainSubstring("# TYPE health_check_duration_seconds histogram\n"))
+		Expect(body).To(ContainSubstring("# TYPE health_check_failures_total counter\n"))
+	})
+
+	t.Run("Should expose the up gauges", func(t *testing.T) {
+		Expect(body).To(ContainSubstring("health_up 0\n"))
+		Expect(body).To(ContainSubstring(`health_check_up{check="foo",fatal="true"} 0`))
+		Expect(body).To(ContainSubstring(`health_check_up{check="bar",fatal="false"} 1`))
+	})
+
+	t.Run("Should expose the failure counts", func(t *testing.T) {
+		Expect(body).To(MatchRegexp(`health_check_contiguous_failures\{check="foo"\} [1-9]`))
+		Expect(body).To(ContainSubstring(`health_check_contiguous_failures{check="bar"} 0`))
+		Expect(body).To(MatchRegexp(`health_check_failures_total\{check="foo"\} [1-9]`))
+		Expect(body).To(ContainSubstring(`health_check_failures_total{check="bar"} 0`))
+	})
+
+	t.Run("Should expose the duration histogram", func(t *testing.T) {
+		Expect(body).To(MatchRegexp(`health_check_duration_seconds_bucket\{check="bar",le="0.005"\} [1-9]`))
+		Expect(body).To(MatchRegexp(`health_check_duration_seconds_bucket\{check="bar",le="\+Inf"\} [1-9]`))
+		Expect(body).To(ContainSubstring(`health_check_duration_seconds_sum{check="bar"}`))
+		Expect(body).To(MatchRegexp(`health_check_duration_seconds_count\{check="bar"\} [1-9]`))
+		Expect(body).To(MatchRegexp(`health_check_last_check_timestamp_seconds\{check="bar"\} \d`))
+	})
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(escapeLabelValue("a\"b\\c\nd")).To(Equal(`a\"b\\c\nd`))
+}
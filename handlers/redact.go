@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/InVisionApp/go-health/v2"
+)
+
+// RedactedValue replaces the errors of checks that are redacted.
+const RedactedValue = "[redacted]"
+
+type contextKey int
+
+// the context key under which "Redact()" records whether the request is authenticated
+const authenticatedKey contextKey = iota
+
+// Redact wraps a handler of this package so that the details and errors of
+// all checks are redacted for requests that `auth` rejects (or for every
+// request, if `auth` is nil). Requests that `auth` accepts see the details
+// and errors of every check, including the ones marked as
+// `health.Config.Sensitive` (which are redacted for requests not served via
+// `Redact`). Credentials in the errors that are shown are always masked (see
+// `health.ScrubCredentials()`).
+func Redact(next http.Handler, auth AuthFunc) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		authenticated := auth != nil && auth(r)
+
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), authenticatedKey, authenticated)))
+	})
+}
+
+// indicates whether the details and errors of the given check are to be
+// redacted for the request
+func redacted(r *http.Request, sensitive bool) bool {
+	authenticated, ok := r.Context().Value(authenticatedKey).(bool)
+	if !ok {
+		// not served via "Redact()"
+		return sensitive
+	}
+
+	return !authenticated
+}
+
+// returns the state as it may be shown to the request
+func redactState(r *http.Request, s health.State) health.State {
+	if redacted(r, s.Sensitive) {
+		s.Details = nil
+
+		if s.Err != "" {
+			s.Err = RedactedValue
+		}
+
+		return s
+	}
+
+	s.Err = health.ScrubCredentials(s.Err)
+
+	return s
+}
+
+// returns the states as they may be shown to the request
+func redactStates(r *http.Request, states map[string]health.State) map[string]health.State {
+	redactedStates := make(map[string]health.State, len(states))
+
+	for name, s := range states {
+		redactedStates[name] = redactState(r, s)
+	}
+
+	return redactedStates
+}
+
+// returns the history entries of the check as they may be shown to the
+// request
+func redactHistory(r *http.Request, sensitive bool, entries []health.HistoryEntry) []health.HistoryEntry {
+	redactedEntries := make([]health.HistoryEntry, len(entries))
+
+	for i, e := range entries {
+		if e.Err != "" {
+			if redacted(r, sensitive) {
+				e.Err = RedactedValue
+			} else {
+				e.Err = health.ScrubCredentials(e.Err)
+			}
+		}
+
+		redactedEntries[i] = e
+	}
+
+	return redactedEntries
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2"
+	"github.com/InVisionApp/go-health/v2/fakes"
+)
+
+func TestRedact(t *testing.T) {
+	RegisterTestingT(t)
+
+	sensitive := newTestCheck("db", false, errors.New("dial postgres://app:secret@db:5432/app: refused"))
+	sensitive.Sensitive = true
+	sensitive.HistorySize = 5
+
+	checker := &fakes.FakeICheckable{}
+	checker.StatusReturns(map[string]string{"node": "cache-1"}, errors.New("redis://:secret@cache:6379 unreachable"))
+
+	h := setupHealth([]*health.Config{
+		sensitive,
+		{Name: "cache", Checker: checker, Interval: testCheckInterval, HistorySize: 5},
+	})
+	defer h.Stop()
+
+	auth := func(r *http.Request) bool {
+		return r.Header.Get("X-Token") == "token"
+	}
+
+	getDetails := func(handler http.Handler, token string) map[string]health.State {
+		req := httptest.NewRequest("GET", "/healthcheck", nil)
+		req.Header.Set("X-Token", token)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		body := struct {
+			Details map[string]health.State `json:"details"`
+		}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		return body.Details
+	}
+
+	t.Run("Should redact sensitive checks and scrub the others without Redact", func(t *testing.T) {
+		details := getDetails(NewJSONHandlerFunc(h, nil), "")
+
+		Expect(details["db"].Err).To(Equal(RedactedValue))
+		Expect(details["cache"].Err).To(Equal("redis://:xxxxx@cache:6379 unreachable"))
+		Expect(details["cache"].Details).ToNot(BeNil())
+	})
+
+	t.Run("Should redact all checks for unauthenticated requests", func(t *testing.T) {
+		details := getDetails(Redact(NewJSONHandlerFunc(h, nil), auth), "wrong")
+
+		Expect(details["db"].Err).To(Equal(RedactedValue))
+		Expect(details["db"].Details).To(BeNil())
+		Expect(details["cache"].Err).To(Equal(RedactedValue))
+		Expect(details["cache"].Details).To(BeNil())
+		Expect(details["cache"].Status).To(Equal(health.StatusFailed))
+
+		// without an auth func, every request is unauthenticated
+		details = getDetails(Redact(NewJSONHandlerFunc(h, nil), nil), "token")
+		Expect(details["cache"].Err).To(Equal(RedactedValue))
+	})
+
+	t.Run("Should show all checks (scrubbed) to authenticated requests", func(t *testing.T) {
+		details := getDetails(Redact(NewJSONHandlerFunc(h, nil), auth), "token")
+
+		Expect(details["db"].Err).To(Equal("dial postgres://app:xxxxx@db:5432/app: refused"))
+		Expect(details["cache"].Err).To(Equal("redis://:xxxxx@cache:6379 unreachable"))
+		Expect(details["cache"].Details).To(Equal(map[string]interface{}{"node": "cache-1"}))
+	})
+
+	t.Run("Should redact the other handlers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Redact(NewCheckHandlerFunc(h, "/health/"), auth).ServeHTTP(rec, httptest.NewRequest("GET", "/health/cache?verbose", nil))
+		Expect(rec.Body.String()).ToNot(ContainSubstring("secret"))
+		Expect(rec.Body.String()).ToNot(ContainSubstring("cache-1"))
+
+		rec = httptest.NewRecorder()
+		NewHealthJSONHandlerFunc(h, nil)(rec, httptest.NewRequest("GET", "/health", nil))
+		Expect(rec.Body.String()).ToNot(ContainSubstring("secret"))
+		Expect(rec.Body.String()).To(ContainSubstring(RedactedValue))
+
+		rec = httptest.NewRecorder()
+		NewHistoryHandlerFunc(h)(rec, httptest.NewRequest("GET", "/healthcheck/history", nil))
+		Expect(rec.Body.String()).ToNot(ContainSubstring("secret"))
+		Expect(rec.Body.String()).To(ContainSubstring(RedactedValue))
+		Expect(rec.Body.String()).To(ContainSubstring("xxxxx"))
+
+		rec = httptest.NewRecorder()
+		NewRunNowHandlerFunc(h, nil)(rec, httptest.NewRequest("POST", "/healthcheck/run", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).ToNot(ContainSubstring("secret"))
+	})
+}
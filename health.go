@@ -6,19 +6,34 @@
 package health
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
 	"github.com/InVisionApp/go-logger"
+
+	"github.com/InVisionApp/go-health/v2/clock"
 )
 
 //go:generate counterfeiter -o ./fakes/icheckable.go . ICheckable
+//go:generate counterfeiter -o ./fakes/icontextcheckable.go . IContextCheckable
 
 var (
-	// ErrNoAddCfgWhenActive is returned when you attempt to add check(s) to an already active healthcheck instance
+	// ErrNoAddCfgWhenActive was returned when attempting to add check(s) to an
+	// already active healthcheck instance.
+	//
+	// Deprecated: checks can now be added while the healthcheck is active.
 	ErrNoAddCfgWhenActive = errors.New("Unable to add new check configuration(s) while healthcheck is active")
 
+	// ErrDuplicateCheck is returned when you attempt to add a check using a name that is already registered
+	ErrDuplicateCheck = errors.New("A check with the same name has already been added")
+
+	// ErrCheckNotFound is returned when you attempt to remove or replace a check that has not been added
+	ErrCheckNotFound = errors.New("No check with the given name has been added")
+
 	// ErrAlreadyRunning is returned when you attempt to "h.Start()" an already running healthcheck
 	ErrAlreadyRunning = errors.New("Healthcheck is already running - nothing to start")
 
@@ -27,6 +42,82 @@ var (
 
 	// ErrEmptyConfigs is returned when you attempt to add an empty slice of configs via "h.AddChecks()"
 	ErrEmptyConfigs = errors.New("Configs appears to be empty - nothing to add")
+
+	// ErrCheckTimeout is recorded as the error of a check that did not complete
+	// within its configured "Config.Timeout"
+	ErrCheckTimeout = errors.New("Health check did not complete within the configured timeout")
+
+	// ErrCheckNotScheduled is recorded as the error of a check that could not
+	// get a free worker (see "Health.MaxConcurrentChecks") within its interval
+	ErrCheckNotScheduled = errors.New("Health check could not be scheduled within its interval")
+
+	// ErrCheckStale is recorded as the error of a check that has not reported a
+	// result within its "Config.MaxAge"
+	ErrCheckStale = errors.New("Health check has not reported a result within its max age")
+
+	// ErrDependencyCycle is returned when the "Config.DependsOn" of the checks
+	// form a cycle
+	ErrDependencyCycle = errors.New("Check dependencies contain a cycle")
+
+	// ErrUnknownDependency is returned when a check depends on (see
+	// "Config.DependsOn") a check that has not been added
+	ErrUnknownDependency = errors.New("A check depends on a check that has not been added")
+
+	// ErrCheckHasDependents is returned when you attempt to remove a check that
+	// other checks depend on (see "Config.DependsOn")
+	ErrCheckHasDependents = errors.New("Check cannot be removed while other checks depend on it")
+
+	// ErrNotRunning is returned when you attempt to run a check on demand (via
+	// "h.RunNow()" or "h.RunAll()") while the healthcheck is not running
+	ErrNotRunning = errors.New("Healthcheck is not running - nothing to run")
+)
+
+// DefaultCheckOnceConcurrency is the number of checks "CheckOnce()" runs
+// concurrently, unless "Health.CheckOnceConcurrency" is set.
+const DefaultCheckOnceConcurrency = 10
+
+// DefaultStopTimeout is how long "Stop()" waits for in-flight checks and
+// "OnComplete" hooks to finish.
+const DefaultStopTimeout = 10 * time.Second
+
+const (
+	// StatusOK is the status of a passing check (and of an overall healthy state)
+	StatusOK = "ok"
+
+	// StatusWarn is the status of a check whose checker returned a "*Warning"
+	StatusWarn = "warn"
+
+	// StatusFailed is the status of a failing check (and of an overall failed state)
+	StatusFailed = "failed"
+
+	// StatusStale is the status of a check that has not reported a fresh result
+	// in time (see "Config.MaxAge"), or could not be scheduled in time; stale
+	// fatal checks fail the overall health
+	StatusStale = "stale"
+
+	// StatusSkipped is the status of a check that was not run because one of
+	// its dependencies (see "Config.DependsOn") has failed; skipped fatal
+	// checks fail the overall health
+	StatusSkipped = "skipped"
+
+	// StatusDegraded is the overall status reported when no fatal check has
+	// failed, but at least one check is reporting a warning
+	StatusDegraded = "degraded"
+)
+
+const (
+	// GroupLiveness is the group of checks that determine whether the service
+	// should be restarted (ie. kubernetes liveness probe)
+	GroupLiveness = "liveness"
+
+	// GroupReadiness is the group of checks that determine whether the service
+	// is able to receive traffic (ie. kubernetes readiness probe)
+	GroupReadiness = "readiness"
+
+	// GroupStartup is the group of checks that determine whether the service
+	// has started (ie. kubernetes startup probe); the group is considered
+	// failed until every check in it (fatal or not) has passed at least once
+	GroupStartup = "startup"
 )
 
 // The IHealth interface can be useful if you plan on replacing the actual health
@@ -35,10 +126,25 @@ var (
 type IHealth interface {
 	AddChecks(cfgs []*Config) error
 	AddCheck(cfg *Config) error
+	RemoveCheck(name string) error
+	ReplaceCheck(cfg *Config) error
+	Checks() []string
 	Start() error
 	Stop() error
+	StopContext(ctx context.Context) error
+	RunNow(name string) (State, error)
+	RunAll(ctx context.Context) (map[string]State, error)
+	CheckOnce(ctx context.Context) (map[string]State, bool, error)
 	State() (map[string]State, bool, error)
+	StateFor(group string) (map[string]State, bool, error)
 	Failed() bool
+	FailedFor(group string) bool
+	FailedStates(states map[string]State) bool
+	Status() string
+	Subscribe(filter EventFilter) (<-chan Event, func())
+	AddStatusListener(l IStatusListener)
+	History(name string) ([]HistoryEntry, error)
+	Metrics() map[string]Metrics
 }
 
 // ICheckable is an interface implemented by a number of bundled checkers such
@@ -51,6 +157,18 @@ type ICheckable interface {
 	Status() (interface{}, error)
 }
 
+// IContextCheckable is an optional extension of the ICheckable interface for
+// checkers that are able to honor a "context.Context". When a checker
+// implements it, "StatusContext()" is called instead of "Status()" and the
+// passed context is cancelled once the check exceeds its "Config.Timeout".
+type IContextCheckable interface {
+	ICheckable
+
+	// StatusContext behaves exactly like "Status()", but should abort any
+	// in-flight work as soon as "ctx" is done.
+	StatusContext(ctx context.Context) (interface{}, error)
+}
+
 // IStatusListener is an interface that handles health check failures and
 // recoveries, primarily for stats recording purposes
 type IStatusListener interface {
@@ -69,6 +187,64 @@ type IStatusListener interface {
 	HealthCheckRecovered(entry *State, recordedFailures int64, failureDurationSeconds float64)
 }
 
+// IDegradedStatusListener is an optional interface that an IStatusListener can
+// additionally implement to be notified about checks entering and leaving the
+// "warn" status.
+type IDegradedStatusListener interface {
+	// HealthCheckDegraded is called when a health check state transitions
+	// to "warn" from any other status.
+	// 	* entry - The recorded state of the health check that triggered the degradation
+	HealthCheckDegraded(entry *State)
+
+	// HealthCheckDegradationCleared is called when a health check state
+	// transitions from "warn" back to "ok".
+	// 	* entry - The recorded state of the health check that cleared the degradation
+	HealthCheckDegradationCleared(entry *State)
+}
+
+// IStaleStatusListener is an optional interface that an IStatusListener can
+// additionally implement to be notified about checks going stale.
+type IStaleStatusListener interface {
+	// HealthCheckStale is called when a health check state transitions to
+	// "stale" from any other status.
+	// 	* entry - The recorded state of the health check that went stale
+	HealthCheckStale(entry *State)
+}
+
+// Warning is an error that checkers can return from "Status()" to signal that a
+// dependency is degraded, but still usable. Such checks are recorded with the
+// "warn" status and never cause the overall health to fail.
+type Warning struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (w *Warning) Error() string {
+	if w.Err == nil {
+		return "warning"
+	}
+
+	return w.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (w *Warning) Unwrap() error {
+	return w.Err
+}
+
+// PanicError is recorded as the error of a check whose checker panicked; the
+// check is marked as failed and the panic value and stack are also made
+// available in "State.Details".
+type PanicError struct {
+	Value interface{}
+	Stack string
+}
+
+// Error returns a message containing the panic value.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("Health check panicked: %v", p.Value)
+}
+
 // Config is a struct used for defining and configuring checks.
 type Config struct {
 	// Name of the check
@@ -80,10 +256,87 @@ type Config struct {
 	// Interval between health checks
 	Interval time.Duration
 
+	// FailingInterval is the interval between health checks while the latest
+	// run of the check has failed (ie. to detect a recovery quickly); defaults
+	// to "Interval".
+	FailingInterval time.Duration
+
+	// InitialDelay is the amount of time to wait before the first run of the
+	// check after it has been started
+	InitialDelay time.Duration
+
+	// Jitter is the maximum random delay added to every scheduled run of the
+	// check, so that instances started at the same time do not run their checks
+	// in lockstep. A zero value disables the jitter (unless "JitterFactor" is set).
+	Jitter time.Duration
+
+	// JitterFactor sets the maximum jitter as a fraction of "Interval" (ie. 0.1
+	// for up to 10% of the interval); only used if "Jitter" is not set.
+	JitterFactor float64
+
+	// FailureBackoff stretches the interval between runs while the check keeps
+	// failing (starting from "FailingInterval", unless "Backoff.Base" is set);
+	// the normal "Interval" is used again once it passes. Nil disables the
+	// backoff.
+	FailureBackoff *Backoff
+
+	// MaxAge is the maximum age of the latest result of the check; once it is
+	// exceeded (ie. because the check is stuck), the check is reported as
+	// "stale". Defaults to "DefaultMaxAgeFactor" times the time between the
+	// latest and the next scheduled run (ie. three times the "Interval" for a
+	// passing check); a negative value disables the staleness detection.
+	MaxAge time.Duration
+
+	// Timeout is the maximum amount of time a single run of the check may
+	// take; slower checks are cancelled and recorded as failed with
+	// "ErrCheckTimeout". A zero value disables the timeout.
+	Timeout time.Duration
+
 	// Fatal marks a failing health check so that the
 	// entire health check request fails with a 500 error
 	Fatal bool
 
+	// Groups the check belongs to (such as "GroupLiveness", "GroupReadiness" or
+	// "GroupStartup"); a failing fatal check only fails the groups it is a
+	// member of when queried via "FailedFor()" and "StateFor()".
+	Groups []string
+
+	// Sensitive marks a health check whose errors and details may contain
+	// secrets (such as connection strings); the handlers redact them unless
+	// the request is authenticated (see "handlers.Redact()").
+	Sensitive bool
+
+	// DependsOn contains the names of the checks this check depends on. While
+	// any of them has failed (or was skipped itself), this check is not run;
+	// it is marked as "skipped" instead, without notifying any status
+	// listeners, and "State.RootCause" names the failing check. The first run
+	// of this check waits until all of its dependencies have reported a
+	// result.
+	DependsOn []string
+
+	// FailureThreshold is the number of consecutive failed runs required before
+	// the check is marked as failed (and "IStatusListener.HealthCheckFailed" is
+	// called); defaults to 1.
+	FailureThreshold int
+
+	// SuccessThreshold is the number of consecutive passing runs required before
+	// a failed check is marked as recovered (and
+	// "IStatusListener.HealthCheckRecovered" is called); defaults to 1.
+	SuccessThreshold int
+
+	// LatencyThreshold is the maximum duration of a passing run; slower runs
+	// are recorded as degraded ("warn"), or as failed if "LatencyFailure" is
+	// set. A zero value disables the threshold.
+	LatencyThreshold time.Duration
+
+	// LatencyFailure causes runs exceeding "LatencyThreshold" to be recorded
+	// as failed instead of degraded
+	LatencyFailure bool
+
+	// HistorySize is the number of past results of the check that are kept
+	// and exposed via "Health.History()"; zero disables the history.
+	HistorySize int
+
 	// Hook that gets called when this health check is complete
 	OnComplete func(state *State)
 }
@@ -94,15 +347,32 @@ type State struct {
 	// Name of the health check
 	Name string `json:"name"`
 
-	// Status of the health check state ("ok" or "failed")
+	// Status of the health check state ("ok", "warn", "failed", "stale" or
+	// "skipped"), taking the configured failure and success thresholds into
+	// account
 	Status string `json:"status"`
 
+	// RawStatus is the status of the latest run of the check, before the
+	// failure and success thresholds were applied
+	RawStatus string `json:"raw_status,omitempty"`
+
 	// Err is the error returned from a failed health check
 	Err string `json:"error,omitempty"`
 
 	// Fatal shows if the check will affect global result
 	Fatal bool `json:"fatal,omitempty"`
 
+	// Groups the check belongs to
+	Groups []string `json:"groups,omitempty"`
+
+	// Sensitive indicates the check is marked as sensitive (see
+	// "Config.Sensitive"); never marshaled
+	Sensitive bool `json:"-"`
+
+	// RootCause is the name of the failing check that caused this check to be
+	// skipped (see "Config.DependsOn")
+	RootCause string `json:"root_cause,omitempty"`
+
 	// Details contains more contextual detail about a
 	// failing health check.
 	Details interface{} `json:"details,omitempty"` // contains JSON message (that can be marshaled)
@@ -110,27 +380,164 @@ type State struct {
 	// CheckTime is the time of the last health check
 	CheckTime time.Time `json:"check_time"`
 
-	ContiguousFailures int64     `json:"num_failures"`     // the number of failures that occurred in a row
-	TimeOfFirstFailure time.Time `json:"first_failure_at"` // the time of the initial transitional failure for any given health check
+	// Duration is how long the last health check took (in nanoseconds when marshaled)
+	Duration time.Duration `json:"duration"`
+
+	// QueueWait is how long the last health check waited for a free worker
+	// (see "Health.MaxConcurrentChecks"); not included in "Duration"
+	QueueWait time.Duration `json:"queue_wait,omitempty"`
+
+	// Latency contains statistics about the durations of the recent runs of
+	// the health check
+	Latency *LatencyStats `json:"latency,omitempty"`
+
+	// NextCheckTime is the time the next run of the health check is scheduled for
+	NextCheckTime time.Time `json:"next_check_time"`
+
+	ContiguousFailures  int64     `json:"num_failures"`            // the number of failures that occurred in a row
+	ContiguousSuccesses int64     `json:"num_successes,omitempty"` // the number of non-failures that occurred in a row
+	TimeOfFirstFailure  time.Time `json:"first_failure_at"`        // the time of the initial transitional failure for any given health check
+
+	lastStatus string // the status of the health check before it went stale or was skipped
 }
 
 // indicates state is failure
 func (s *State) isFailure() bool {
-	return s.Status == "failed"
+	return s.Status == StatusFailed
+}
+
+// indicates state is stale
+func (s *State) isStale() bool {
+	return s.Status == StatusStale
+}
+
+// indicates state is skipped
+func (s *State) isSkipped() bool {
+	return s.Status == StatusSkipped
+}
+
+// indicates the check did not report a healthy result (ie. it has failed, is
+// stale or was skipped)
+func (s *State) isUnhealthy() bool {
+	return s.isFailure() || s.isStale() || s.isSkipped()
+}
+
+// returns the status of the latest run of the health check, looking past the
+// stale and skipped statuses
+func (s *State) lastRunStatus() string {
+	if s.isStale() || s.isSkipped() {
+		return s.lastStatus
+	}
+
+	return s.Status
+}
+
+// indicates state is a warning
+func (s *State) isWarning() bool {
+	return s.Status == StatusWarn
+}
+
+// indicates the check is a member of the given group
+func (s *State) inGroup(group string) bool {
+	return containsString(s.Groups, group)
 }
 
 // Health contains internal go-health internal structures.
 type Health struct {
 	Logger log.Logger
 
-	// StatusListener will report failures and recoveries
+	// StatusListener will report failures and recoveries; more listeners can
+	// be registered with "AddStatusListener()"
 	StatusListener IStatusListener
 
-	active     *sBool // indicates whether the healthcheck is actively running
-	configs    []*Config
-	states     map[string]State
-	statesLock sync.Mutex
-	runners    map[string]chan struct{} // contains map of active runners w/ a stop channel
+	// SyncStatusListeners calls the status listeners synchronously, in the
+	// order the transitions happen, instead of in their own goroutines. The
+	// listeners are then called while the new state is being recorded, so they
+	// must return quickly and must not call back into "Health".
+	SyncStatusListeners bool
+
+	// Aggregator determines whether the overall health (or the health of a
+	// group) has failed, given the states of the checks; defaults to
+	// "FatalAggregator".
+	Aggregator Aggregator
+
+	// PreserveStates keeps the last known check states (and history) when the
+	// healthcheck is stopped, so that they remain available until the checks
+	// report again after a restart.
+	PreserveStates bool
+
+	// CheckOnceConcurrency limits the number of checks run concurrently by
+	// "CheckOnce()"; defaults to "DefaultCheckOnceConcurrency".
+	CheckOnceConcurrency int
+
+	// SpreadStart delays the first run of every check by a random amount of
+	// time within its "Interval" (in addition to "Config.InitialDelay"), so
+	// that the initial runs are spread out instead of all firing at "Start()".
+	SpreadStart bool
+
+	// MaxConcurrentChecks limits the number of checks that run at the same
+	// time; runs beyond the limit are queued until a worker is free. A run that
+	// cannot get a worker within the interval of its check is skipped and the
+	// check is marked as stale. A zero value means no limit. Changes take
+	// effect on the next "Start()".
+	MaxConcurrentChecks int
+
+	// Clock provides the time the checks are scheduled and recorded with;
+	// defaults to the system clock. Meant for tests (see "fakes.FakeClock").
+	// Changes take effect on the next "Start()".
+	Clock clock.Clock
+
+	active      *sBool // indicates whether the healthcheck is actively running
+	configs     []*Config
+	states      map[string]State
+	statesLock  sync.Mutex
+	passed      map[string]bool           // checks that have passed at least once, guarded by statesLock
+	histories   map[string]*history       // recent results of each check, guarded by statesLock
+	latencies   map[string]*latencyWindow // recent durations of each check, guarded by statesLock
+	metrics     map[string]*Metrics       // cumulative counters of each check, guarded by statesLock
+	runners     map[string]*runner        // contains map of active runners
+	running     *sync.WaitGroup           // tracks the runners (and hooks) started since the last "Start()"
+	workers     chan struct{}             // holds a token for each busy worker; nil if unlimited
+	runnersLock sync.Mutex                // guards configs, runners and running
+	subs        map[*subscriber]struct{}  // event subscribers
+	subsLock    sync.Mutex                // guards subs; taken after runnersLock and statesLock
+
+	listeners     []IStatusListener // listeners added with "AddStatusListener()"
+	listenersLock sync.Mutex        // guards listeners
+}
+
+// runner holds the handles of a running check
+type runner struct {
+	cfg    *Config
+	ctx    context.Context    // passed to the checker; cancelled once the runner is no longer needed
+	cancel context.CancelFunc // aborts the in-flight run (if any)
+	stop   chan struct{}      // closed to stop scheduling further runs
+	wg     *sync.WaitGroup    // the wait group the runner (and its hooks) are tracked by
+	pool   chan struct{}      // the worker pool the runs are executed in; nil if unlimited
+	mu     sync.Mutex         // guards call and next
+	call   *runCall           // the in-flight run (if any)
+	next   time.Time          // the time the next run is scheduled for
+	stale  clock.Timer        // marks the latest state as stale once it is too old
+	clock  clock.Clock        // the clock the runs are scheduled with
+
+	// notified when a run happened outside of the schedule
+	rescheduled chan struct{}
+
+	// closed once the first state of the check has been recorded (or the
+	// runner was stopped); dependents hold their first run until then
+	recorded     chan struct{}
+	recordedOnce sync.Once
+
+	// the "recorded" channels of the dependencies of the check
+	dependencies []<-chan struct{}
+}
+
+// runCall is a single run of a check, shared by everyone asking for a run
+// while it is in flight
+type runCall struct {
+	done    chan struct{} // closed once the run has completed
+	state   *State        // the recorded state; nil if the runner was stopped in the meantime
+	waiters int           // the number of callers waiting for the run, besides the one running it
 }
 
 // New returns a new instance of the Health struct.
@@ -139,7 +546,13 @@ func New() *Health {
 		Logger:     log.NewSimple(),
 		configs:    make([]*Config, 0),
 		states:     make(map[string]State, 0),
-		runners:    make(map[string]chan struct{}, 0),
+		passed:     make(map[string]bool, 0),
+		histories:  make(map[string]*history, 0),
+		latencies:  make(map[string]*latencyWindow, 0),
+		metrics:    make(map[string]*Metrics, 0),
+		runners:    make(map[string]*runner, 0),
+		running:    &sync.WaitGroup{},
+		subs:       make(map[*subscriber]struct{}, 0),
 		active:     newBool(),
 		statesLock: sync.Mutex{},
 	}
@@ -151,75 +564,195 @@ func (h *Health) DisableLogging() {
 }
 
 // AddChecks is used for adding multiple check definitions at once (as opposed
-// to adding them sequentially via "AddCheck()").
+// to adding them sequentially via "AddCheck()"). If the healthcheck is already
+// active, the new checks will start running immediately (as long as their
+// dependencies are valid, see "Start()").
 func (h *Health) AddChecks(cfgs []*Config) error {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
+	names := make(map[string]bool, len(cfgs))
+
+	for _, c := range cfgs {
+		if names[c.Name] || h.configIndex(c.Name) >= 0 {
+			return ErrDuplicateCheck
+		}
+
+		names[c.Name] = true
+	}
+
 	if h.active.val() {
-		return ErrNoAddCfgWhenActive
+		if err := validateDependencies(append(append([]*Config{}, h.configs...), cfgs...)); err != nil {
+			return err
+		}
 	}
 
-	h.configs = append(h.configs, cfgs...)
+	for _, c := range cfgs {
+		h.configs = append(h.configs, c)
+		h.publish(EventAdded, c.Name, nil)
+	}
+
+	if h.active.val() {
+		h.startChecks(cfgs)
+	}
 
 	return nil
 }
 
 // AddCheck is used for adding a single check definition to the current health
-// instance.
+// instance. If the healthcheck is already active, the check will start running
+// immediately.
 func (h *Health) AddCheck(cfg *Config) error {
+	return h.AddChecks([]*Config{cfg})
+}
+
+// RemoveCheck will stop the check with the given name (if it is running) and
+// remove both its definition and its state from the current health instance.
+// "ErrCheckHasDependents" is returned while other checks depend on it.
+func (h *Health) RemoveCheck(name string) error {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
+	idx := h.configIndex(name)
+	if idx < 0 {
+		return ErrCheckNotFound
+	}
+
+	for _, c := range h.configs {
+		if containsString(c.DependsOn, name) {
+			return ErrCheckHasDependents
+		}
+	}
+
+	h.configs = append(h.configs[:idx], h.configs[idx+1:]...)
+	h.abortCheck(name)
+	h.safeDeleteState(name)
+	h.publish(EventRemoved, name, nil)
+
+	return nil
+}
+
+// ReplaceCheck will swap out the definition of the check that has the same name
+// as "cfg". The state of the previous check is discarded and, if the
+// healthcheck is active, the new check will start running immediately.
+func (h *Health) ReplaceCheck(cfg *Config) error {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
+	idx := h.configIndex(cfg.Name)
+	if idx < 0 {
+		return ErrCheckNotFound
+	}
+
 	if h.active.val() {
-		return ErrNoAddCfgWhenActive
+		cfgs := append([]*Config{}, h.configs...)
+		cfgs[idx] = cfg
+
+		if err := validateDependencies(cfgs); err != nil {
+			return err
+		}
+	}
+
+	h.configs[idx] = cfg
+	h.abortCheck(cfg.Name)
+	h.safeDeleteState(cfg.Name)
+	h.publish(EventRemoved, cfg.Name, nil)
+	h.publish(EventAdded, cfg.Name, nil)
+
+	if h.active.val() {
+		h.startChecks([]*Config{cfg})
 	}
 
-	h.configs = append(h.configs, cfg)
 	return nil
 }
 
 // Start will start all of the defined health checks. Each of the checks run in
-// their own goroutines (as "time.Ticker").
+// their own goroutines (on a timer). The healthcheck may also be started without
+// any checks; checks added later on start running right away.
+// "ErrUnknownDependency" or "ErrDependencyCycle" is returned if the
+// "Config.DependsOn" of the checks are invalid.
 func (h *Health) Start() error {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
 	if h.active.val() {
 		return ErrAlreadyRunning
 	}
 
-	// if there are no check configs, this is a noop
-	if len(h.configs) < 1 {
-		return nil
+	if err := validateDependencies(h.configs); err != nil {
+		return err
 	}
 
-	for _, c := range h.configs {
-		h.Logger.WithFields(log.Fields{"name": c.Name}).Debug("Starting checker")
-		ticker := time.NewTicker(c.Interval)
-		stop := make(chan struct{})
-
-		h.startRunner(c, ticker, stop)
+	// runners left over from a previous (timed out) stop are still tracked by
+	// the previous wait group
+	h.running = &sync.WaitGroup{}
+	h.workers = nil
 
-		h.runners[c.Name] = stop
+	if h.MaxConcurrentChecks > 0 {
+		h.workers = make(chan struct{}, h.MaxConcurrentChecks)
 	}
 
+	h.startChecks(h.configs)
+
 	// Checkers are now actively running
 	h.active.setTrue()
 
 	return nil
 }
 
-// Stop will cause all of the running health checks to be stopped. Additionally,
-// all existing check states will be reset.
+// Stop will cause all of the running health checks to be stopped and waits for
+// in-flight checks and "OnComplete" hooks to finish, for at most
+// "DefaultStopTimeout" (see "StopContext()"). Additionally, all existing check
+// states will be reset (unless "PreserveStates" is set). Once stopped, the
+// healthcheck can be started again.
 func (h *Health) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultStopTimeout)
+	defer cancel()
+
+	return h.StopContext(ctx)
+}
+
+// StopContext behaves like "Stop()", but waits for in-flight checks and
+// "OnComplete" hooks until "ctx" is done; in that case the context error is
+// returned. In-flight checks are cancelled right away (see "IContextCheckable")
+// and their results are discarded. The healthcheck is stopped either way.
+func (h *Health) StopContext(ctx context.Context) error {
+	h.runnersLock.Lock()
+
 	if !h.active.val() {
+		h.runnersLock.Unlock()
 		return ErrAlreadyStopped
 	}
 
-	for name, stop := range h.runners {
-		h.Logger.WithFields(log.Fields{"name": name}).Debug("Stopping checker")
-		close(stop)
+	for name := range h.runners {
+		h.abortCheck(name)
 	}
 
-	// Reset runner map
-	h.runners = make(map[string]chan struct{}, 0)
+	h.active.setFalse()
 
-	// Reset states
-	h.safeResetStates()
+	if !h.PreserveStates {
+		h.safeResetStates()
+	}
 
-	return nil
+	running := h.running
+
+	// hooks may call back into the healthcheck, so do not hold the lock while
+	// waiting for them
+	h.runnersLock.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // State will return a map of all current healthcheck states (thread-safe), a
@@ -234,68 +767,704 @@ func (h *Health) State() (map[string]State, bool, error) {
 	return h.safeGetStates(), h.Failed(), nil
 }
 
+// StateFor behaves like "State()", but only returns the states of the checks
+// that are members of the given group; the returned bool indicates whether the
+// group has failed (see "FailedFor()").
+func (h *Health) StateFor(group string) (map[string]State, bool, error) {
+	groupStates := make(map[string]State, 0)
+
+	for name, val := range h.safeGetStates() {
+		if val.inGroup(group) {
+			groupStates[name] = val
+		}
+	}
+
+	if group == GroupStartup {
+		return groupStates, !h.startupComplete(), nil
+	}
+
+	return groupStates, h.aggregator().Failed(groupStates), nil
+}
+
 // Failed will return the basic state of overall health. This should be used when
 // details about the failure are not needed
 func (h *Health) Failed() bool {
-	for _, val := range h.safeGetStates() {
-		if val.Fatal && val.isFailure() {
-			return true
+	return h.aggregator().Failed(h.safeGetStates())
+}
+
+// FailedStates will return whether the given states (such as a subset of the
+// states returned by "State()") have failed, as determined by the configured
+// "Aggregator".
+func (h *Health) FailedStates(states map[string]State) bool {
+	return h.aggregator().Failed(states)
+}
+
+// FailedFor will return whether the given group has failed, ie. whether any
+// fatal check in it has failed (or as determined by the configured
+// "Aggregator" from the states of the checks in the group). The "GroupStartup"
+// group is reported as failed until every check in it has passed at least
+// once; afterwards it never fails again (until the healthcheck is
+// restarted).
+func (h *Health) FailedFor(group string) bool {
+	_, failed, _ := h.StateFor(group)
+	return failed
+}
+
+// Status will return the overall health as a string: "failed" if "Failed()"
+// returns true, "degraded" if any check is reporting a warning and "ok" otherwise.
+func (h *Health) Status() string {
+	states := h.safeGetStates()
+
+	if h.aggregator().Failed(states) {
+		return StatusFailed
+	}
+
+	for _, val := range states {
+		if val.isWarning() {
+			return StatusDegraded
 		}
 	}
-	return false
+
+	return StatusOK
+}
+
+// creates and registers (but does not start) a runner for the given check;
+// caller must hold runnersLock
+func (h *Health) newRunner(cfg *Config) *runner {
+	h.Logger.WithFields(log.Fields{"name": cfg.Name}).Debug("Starting checker")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &runner{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+		wg:     h.running,
+		pool:   h.workers,
+		clock:  h.clock(),
+
+		rescheduled: make(chan struct{}, 1),
+		recorded:    make(chan struct{}),
+	}
+
+	h.runners[cfg.Name] = r
+
+	return r
+}
+
+// starts the runners of the given checks; all of them are registered before
+// any is started, so that their first runs can wait for their dependencies.
+// Caller must hold runnersLock
+func (h *Health) startChecks(cfgs []*Config) {
+	runners := make([]*runner, 0, len(cfgs))
+
+	for _, c := range cfgs {
+		runners = append(runners, h.newRunner(c))
+	}
+
+	for _, r := range runners {
+		for _, name := range r.cfg.DependsOn {
+			if dep, ok := h.runners[name]; ok {
+				r.dependencies = append(r.dependencies, dep.recorded)
+			}
+		}
+
+		h.startRunner(r)
+	}
+}
+
+// stops the runner of the given check (if any) and returns it; the in-flight
+// run (if any) is left to complete. Caller must hold runnersLock
+func (h *Health) stopCheck(name string) *runner {
+	r, ok := h.runners[name]
+	if !ok {
+		return nil
+	}
+
+	h.Logger.WithFields(log.Fields{"name": name}).Debug("Stopping checker")
+	close(r.stop)
+	delete(h.runners, name)
+	r.markRecorded()
+
+	r.mu.Lock()
+	if r.stale != nil {
+		r.stale.Stop()
+	}
+	r.mu.Unlock()
+
+	return r
+}
+
+// stops the runner of the given check (if any) and cancels its in-flight run;
+// caller must hold runnersLock
+func (h *Health) abortCheck(name string) {
+	if r := h.stopCheck(name); r != nil {
+		r.cancel()
+	}
+}
+
+// returns the index of the named check config or -1; caller must hold runnersLock
+func (h *Health) configIndex(name string) int {
+	for i, c := range h.configs {
+		if c.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Checks will return the names of the added checks (whether or not they have
+// reported a result yet), in the order they were added.
+func (h *Health) Checks() []string {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
+	names := make([]string, 0, len(h.configs))
+
+	for _, c := range h.configs {
+		names = append(names, c.Name)
+	}
+
+	return names
 }
 
-func (h *Health) startRunner(cfg *Config, ticker *time.Ticker, stop <-chan struct{}) {
+// History will return the recorded results of the named check, oldest first.
+// The number of kept results is determined by "Config.HistorySize".
+func (h *Health) History(name string) ([]HistoryEntry, error) {
+	h.runnersLock.Lock()
+	found := h.configIndex(name) >= 0
+	h.runnersLock.Unlock()
+
+	if !found {
+		return nil, ErrCheckNotFound
+	}
+
+	h.statesLock.Lock()
+	defer h.statesLock.Unlock()
+
+	if hist, ok := h.histories[name]; ok {
+		return hist.list(), nil
+	}
 
-	// function to execute and collect check data
-	checkFunc := func() {
-		data, err := cfg.Checker.Status()
+	return []HistoryEntry{}, nil
+}
+
+// Metrics will return the cumulative counters (number of runs, failures and a
+// duration histogram) of every check that has run at least once, keyed by the
+// name of the check.
+func (h *Health) Metrics() map[string]Metrics {
+	h.statesLock.Lock()
+	defer h.statesLock.Unlock()
+
+	metrics := make(map[string]Metrics, len(h.metrics))
+
+	for name, m := range h.metrics {
+		metrics[name] = m.copy()
+	}
+
+	return metrics
+}
+
+// indicates whether every startup check has passed at least once
+func (h *Health) startupComplete() bool {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
+	h.statesLock.Lock()
+	defer h.statesLock.Unlock()
+
+	for _, c := range h.configs {
+		if containsString(c.Groups, GroupStartup) && !h.passed[c.Name] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (h *Health) startRunner(r *runner) {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		// execute once so that it is immediate (unless delayed or waiting for
+		// the first results of its dependencies)
+		if wait(r.clock, h.initialDelay(r.cfg), r.stop) && r.awaitDependencies() {
+			h.runCheck(r)
+
+			timer := r.clock.NewTimer(r.untilNextRun())
+			defer timer.Stop()
+
+			// all following executions
+		RunLoop:
+			for {
+				select {
+				case <-timer.C():
+					h.runCheck(r)
+					timer.Reset(r.untilNextRun())
+				case <-r.rescheduled:
+					if !timer.Stop() {
+						// drain the expired timer, if not done already
+						select {
+						case <-timer.C():
+						default:
+						}
+					}
+
+					timer.Reset(r.untilNextRun())
+				case <-r.stop:
+					break RunLoop
+				}
+			}
+		}
+
+		h.Logger.WithFields(log.Fields{"name": r.cfg.Name}).Debug("Checker exiting")
+	}()
+}
+
+// executes and records a single run of the check; if a run is already in
+// flight, its result is waited for and returned instead. The returned state is
+// nil if the runner was stopped while the check was running.
+func (h *Health) runCheck(r *runner) *State {
+	r.mu.Lock()
+
+	if c := r.call; c != nil {
+		c.waiters++
+		r.mu.Unlock()
+		<-c.done
+
+		return c.state
+	}
 
-		stateEntry := &State{
+	c := &runCall{done: make(chan struct{})}
+	r.call = c
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.call = nil
+		r.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	cfg := r.cfg
+
+	var stateEntry *State
+
+	if rootCause := h.safeGetRootCause(cfg); rootCause != "" {
+		stateEntry = h.skippedState(cfg, rootCause)
+	} else if queueWait, ok := r.acquireWorker(); ok {
+		stateEntry = h.executeCheck(r.ctx, cfg)
+		stateEntry.QueueWait = queueWait
+		r.releaseWorker()
+	} else {
+		h.Logger.WithFields(log.Fields{
+			"check": cfg.Name,
+			"fatal": cfg.Fatal,
+			"wait":  queueWait,
+		}).Warn("healthcheck could not be scheduled")
+
+		stateEntry = &State{
 			Name:      cfg.Name,
-			Status:    "ok",
-			Details:   data,
-			CheckTime: time.Now(),
+			Status:    StatusStale,
+			Err:       ErrCheckNotScheduled.Error(),
+			CheckTime: r.clock.Now(),
+			QueueWait: queueWait,
 			Fatal:     cfg.Fatal,
+			Groups:    cfg.Groups,
+			Sensitive: cfg.Sensitive,
 		}
+	}
+
+	if !h.safeUpdateState(cfg, stateEntry, r.stop) {
+		// check was removed or stopped while it was running
+		return nil
+	}
+
+	c.state = stateEntry
+	r.markRecorded()
+
+	r.mu.Lock()
+	r.next = stateEntry.NextCheckTime
+	h.watchStaleness(r, stateEntry)
+	r.mu.Unlock()
+
+	if cfg.OnComplete != nil {
+		r.wg.Add(1)
+
+		go func() {
+			defer r.wg.Done()
+			cfg.OnComplete(stateEntry)
+		}()
+	}
+
+	return stateEntry
+}
+
+// (re)arms the timer of the runner that marks the given (recorded) state as
+// stale once it exceeds its max age; caller must hold r.mu
+func (h *Health) watchStaleness(r *runner, stateEntry *State) {
+	if r.stale != nil {
+		r.stale.Stop()
+		r.stale = nil
+	}
+
+	maxAge := r.cfg.maxAge(stateEntry)
+	if maxAge <= 0 {
+		return
+	}
+
+	checkTime := stateEntry.CheckTime
+
+	r.stale = r.clock.AfterFunc(maxAge, func() {
+		h.safeMarkStale(r, checkTime)
+	})
+}
+
+// RunNow will immediately run the named check (outside of its regular
+// interval), record its result and return the fresh state. If the check is
+// already running, its result is waited for and returned instead of starting
+// another run. "ErrNotRunning" is returned if the healthcheck is not running.
+func (h *Health) RunNow(name string) (State, error) {
+	r, err := h.acquireRunner(name)
+	if err != nil {
+		return State{}, err
+	}
+
+	defer r.wg.Done()
+
+	stateEntry := h.runCheck(r)
+	if stateEntry == nil {
+		return State{}, ErrNotRunning
+	}
+
+	r.reschedule()
+
+	return *stateEntry, nil
+}
+
+// RunAll will immediately run all of the checks (see "RunNow()") concurrently
+// and return their fresh states, keyed by the name of the check. If "ctx" is
+// done before all of the checks have completed, the states collected so far are
+// returned along with the context error; the remaining runs still complete and
+// are recorded in the background.
+func (h *Health) RunAll(ctx context.Context) (map[string]State, error) {
+	h.runnersLock.Lock()
+
+	if !h.active.val() {
+		h.runnersLock.Unlock()
+		return nil, ErrNotRunning
+	}
+
+	runners := make([]*runner, 0, len(h.runners))
+
+	for _, r := range h.runners {
+		r.wg.Add(1)
+		runners = append(runners, r)
+	}
+
+	h.runnersLock.Unlock()
+
+	type result struct {
+		name  string
+		state *State
+	}
+
+	// buffered so that the runs can complete after we stopped waiting
+	results := make(chan result, len(runners))
+
+	for _, r := range runners {
+		go func(r *runner) {
+			defer r.wg.Done()
+
+			stateEntry := h.runCheck(r)
+			r.reschedule()
+
+			results <- result{r.cfg.Name, stateEntry}
+		}(r)
+	}
+
+	states := make(map[string]State, len(runners))
+
+	for range runners {
+		select {
+		case res := <-results:
+			if res.state != nil {
+				states[res.name] = *res.state
+			}
+		case <-ctx.Done():
+			return states, ctx.Err()
+		}
+	}
+
+	return states, nil
+}
 
-		if err != nil {
-			h.Logger.WithFields(log.Fields{
-				"check": cfg.Name,
-				"fatal": cfg.Fatal,
-				"err":   err,
-			}).Error("healthcheck has failed")
+// CheckOnce will run every registered check exactly once (at most
+// "CheckOnceConcurrency" at a time) and return their states, keyed by the name
+// of the check, along with a bool indicating whether the overall health has
+// failed (see "Health.Aggregator"). It does not require the healthcheck to be
+// started and never touches the states recorded by a running healthcheck;
+// failure and success thresholds do not apply. This is intended for one-shot
+// usage, such as CLIs and exec probes.
+//
+// Checks run after their dependencies (see "Config.DependsOn") and are skipped
+// if any of them has failed. "ErrUnknownDependency" or "ErrDependencyCycle" is
+// returned if the dependencies are invalid.
+//
+// "ctx" is passed to context aware checkers (see "IContextCheckable"). If it is
+// done before all of the checks have completed, the states collected so far
+// are returned (as failed) along with the context error.
+func (h *Health) CheckOnce(ctx context.Context) (map[string]State, bool, error) {
+	h.runnersLock.Lock()
+	cfgs := append([]*Config{}, h.configs...)
+	h.runnersLock.Unlock()
+
+	if err := validateDependencies(cfgs); err != nil {
+		return nil, true, err
+	}
+
+	concurrency := h.CheckOnceConcurrency
+	if concurrency < 1 {
+		concurrency = DefaultCheckOnceConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	// buffered so that the checks can complete after we stopped waiting
+	results := make(chan *State, len(cfgs))
+
+	// every check waits for the states of its dependencies before it runs (or
+	// is skipped); "done" is closed once the state of the named check is in
+	var (
+		mu        sync.Mutex
+		collected = make(map[string]State, len(cfgs))
+		done      = make(map[string]chan struct{}, len(cfgs))
+	)
+
+	for _, cfg := range cfgs {
+		done[cfg.Name] = make(chan struct{})
+	}
+
+	for _, cfg := range cfgs {
+		go func(cfg *Config) {
+			defer close(done[cfg.Name])
+
+			for _, name := range cfg.DependsOn {
+				select {
+				case <-done[name]:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			mu.Lock()
+			rootCause := rootCause(cfg, collected)
+			mu.Unlock()
 
-			stateEntry.Err = err.Error()
-			stateEntry.Status = "failed"
+			var stateEntry *State
+
+			if rootCause != "" {
+				stateEntry = h.skippedState(cfg, rootCause)
+			} else {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				stateEntry = h.executeCheck(ctx, cfg)
+				<-sem
+			}
+
+			stateEntry.RawStatus = stateEntry.Status
+
+			if stateEntry.isFailure() {
+				stateEntry.ContiguousFailures = 1
+				stateEntry.TimeOfFirstFailure = stateEntry.CheckTime
+			} else if !stateEntry.isSkipped() {
+				stateEntry.ContiguousSuccesses = 1
+			}
+
+			mu.Lock()
+			collected[cfg.Name] = *stateEntry
+			mu.Unlock()
+
+			results <- stateEntry
+		}(cfg)
+	}
+
+	states := make(map[string]State, len(cfgs))
+
+	for range cfgs {
+		select {
+		case stateEntry := <-results:
+			states[stateEntry.Name] = *stateEntry
+		case <-ctx.Done():
+			return states, true, ctx.Err()
 		}
+	}
 
-		h.safeUpdateState(stateEntry)
+	return states, h.aggregator().Failed(states), nil
+}
 
-		if cfg.OnComplete != nil {
-			go cfg.OnComplete(stateEntry)
+// returns the state of a check that was not run because of its failing
+// dependency "rootCause"
+func (h *Health) skippedState(cfg *Config, rootCause string) *State {
+	return &State{
+		Name:      cfg.Name,
+		Status:    StatusSkipped,
+		Err:       fmt.Sprintf("Skipped since dependency '%s' has failed", rootCause),
+		RootCause: rootCause,
+		CheckTime: h.clock().Now(),
+		Fatal:     cfg.Fatal,
+		Groups:    cfg.Groups,
+		Sensitive: cfg.Sensitive,
+	}
+}
+
+// returns the runner of the named check, tracked by its wait group so that a
+// stop waits for the caller to be done with it (via "r.wg.Done()")
+func (h *Health) acquireRunner(name string) (*runner, error) {
+	h.runnersLock.Lock()
+	defer h.runnersLock.Unlock()
+
+	if h.configIndex(name) < 0 {
+		return nil, ErrCheckNotFound
+	}
+
+	r, ok := h.runners[name]
+	if !ok {
+		return nil, ErrNotRunning
+	}
+
+	r.wg.Add(1)
+
+	return r, nil
+}
+
+// runs the check once and returns the resulting state entry, which has yet to
+// be recorded
+func (h *Health) executeCheck(ctx context.Context, cfg *Config) *State {
+	start := h.clock().Now()
+	data, err := runChecker(ctx, cfg)
+	duration := h.clock().Since(start)
+
+	stateEntry := &State{
+		Name:      cfg.Name,
+		Status:    StatusOK,
+		Details:   data,
+		CheckTime: h.clock().Now(),
+		Duration:  duration,
+		Fatal:     cfg.Fatal,
+		Groups:    cfg.Groups,
+		Sensitive: cfg.Sensitive,
+	}
+
+	if err == nil && cfg.LatencyThreshold > 0 && duration > cfg.LatencyThreshold {
+		err = fmt.Errorf("Check took %v, exceeding the latency threshold of %v", duration, cfg.LatencyThreshold)
+
+		if !cfg.LatencyFailure {
+			err = &Warning{Err: err}
 		}
 	}
 
+	var (
+		warning  *Warning
+		panicErr *PanicError
+	)
+
+	if errors.As(err, &panicErr) {
+		h.Logger.WithFields(log.Fields{
+			"check": cfg.Name,
+			"fatal": cfg.Fatal,
+			"err":   err,
+			"stack": panicErr.Stack,
+		}).Error("healthcheck has panicked")
+
+		stateEntry.Err = err.Error()
+		stateEntry.Status = StatusFailed
+		stateEntry.Details = map[string]interface{}{
+			"panic": fmt.Sprintf("%v", panicErr.Value),
+			"stack": panicErr.Stack,
+		}
+	} else if errors.As(err, &warning) {
+		h.Logger.WithFields(log.Fields{
+			"check": cfg.Name,
+			"fatal": cfg.Fatal,
+			"err":   err,
+		}).Warn("healthcheck is degraded")
+
+		stateEntry.Err = err.Error()
+		stateEntry.Status = StatusWarn
+	} else if err != nil {
+		h.Logger.WithFields(log.Fields{
+			"check": cfg.Name,
+			"fatal": cfg.Fatal,
+			"err":   err,
+		}).Error("healthcheck has failed")
+
+		stateEntry.Err = err.Error()
+		stateEntry.Status = StatusFailed
+	}
+
+	return stateEntry
+}
+
+// executes the checker, abandoning it if it does not complete within the
+// configured timeout
+func runChecker(ctx context.Context, cfg *Config) (interface{}, error) {
+	if cfg.Timeout <= 0 {
+		return checkStatus(ctx, cfg.Checker)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	type result struct {
+		data interface{}
+		err  error
+	}
+
+	// buffered so that a checker ignoring its context can still exit once it
+	// eventually returns
+	resultCh := make(chan result, 1)
+
 	go func() {
-		defer ticker.Stop()
-
-		// execute once so that it is immediate
-		checkFunc()
-
-		// all following executions
-	RunLoop:
-		for {
-			select {
-			case <-ticker.C:
-				checkFunc()
-			case <-stop:
-				break RunLoop
-			}
+		data, err := checkStatus(ctx, cfg.Checker)
+		resultCh <- result{data, err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil && ctx.Err() == context.DeadlineExceeded {
+			return nil, ErrCheckTimeout
 		}
+		return res.data, res.err
+	case <-ctx.Done():
+		return nil, ErrCheckTimeout
+	}
+}
 
-		h.Logger.WithFields(log.Fields{"name": cfg.Name}).Debug("Checker exiting")
+// calls the context aware status func if the checker provides one; a panic
+// in the checker is recovered and returned as a "*PanicError"
+func checkStatus(ctx context.Context, checker ICheckable) (data interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			data = nil
+			err = &PanicError{Value: r, Stack: string(debug.Stack())}
+		}
 	}()
+
+	if c, ok := checker.(IContextCheckable); ok {
+		return c.StatusContext(ctx)
+	}
+
+	return checker.Status()
 }
 
 // resets the states in a concurrency-safe manner
@@ -303,18 +1472,94 @@ func (h *Health) safeResetStates() {
 	h.statesLock.Lock()
 	defer h.statesLock.Unlock()
 	h.states = make(map[string]State, 0)
+	h.passed = make(map[string]bool, 0)
+	h.histories = make(map[string]*history, 0)
+	h.latencies = make(map[string]*latencyWindow, 0)
+	h.metrics = make(map[string]*Metrics, 0)
 }
 
-// updates the check state in a concurrency-safe manner
-func (h *Health) safeUpdateState(stateEntry *State) {
+// updates the check state (and history) in a concurrency-safe manner; the state
+// is discarded (and false returned) if the runner has been stopped in the meantime
+func (h *Health) safeUpdateState(cfg *Config, stateEntry *State, stop <-chan struct{}) bool {
+	h.statesLock.Lock()
+	defer h.statesLock.Unlock()
+
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+
+	if stateEntry.isStale() || stateEntry.isSkipped() {
+		h.updateNotRunState(cfg, stateEntry)
+		return true
+	}
+
+	window, ok := h.latencies[stateEntry.Name]
+	if !ok {
+		window = newLatencyWindow(latencyWindowSize)
+		h.latencies[stateEntry.Name] = window
+	}
+
+	window.add(stateEntry.Duration)
+	stateEntry.Latency = window.stats()
+
+	prevState := h.states[stateEntry.Name]
+
+	// schedule the next run, backing off while the check keeps failing
+	var failures int64
+	if stateEntry.isFailure() {
+		failures = prevState.ContiguousFailures + 1
+	}
+
+	stateEntry.NextCheckTime = stateEntry.CheckTime.Add(cfg.nextInterval(failures) + cfg.jitter())
+
 	// dispatch any status listeners
-	h.handleStatusListener(stateEntry)
+	h.handleStatusListener(cfg, prevState, stateEntry)
+
+	if stateEntry.RawStatus != StatusFailed {
+		h.passed[stateEntry.Name] = true
+	}
 
-	// update states here
+	metrics, ok := h.metrics[stateEntry.Name]
+	if !ok {
+		metrics = newMetrics()
+		h.metrics[stateEntry.Name] = metrics
+	}
+
+	metrics.observe(stateEntry)
+
+	if cfg.HistorySize > 0 {
+		hist, ok := h.histories[stateEntry.Name]
+		if !ok {
+			hist = newHistory(cfg.HistorySize)
+			h.histories[stateEntry.Name] = hist
+		}
+
+		hist.add(HistoryEntry{
+			Status:    stateEntry.RawStatus,
+			Err:       stateEntry.Err,
+			CheckTime: stateEntry.CheckTime,
+			Duration:  stateEntry.Duration,
+		})
+	}
+
+	h.states[stateEntry.Name] = *stateEntry
+	h.publish(EventResult, stateEntry.Name, stateEntry)
+
+	return true
+}
+
+// removes the state of a check in a concurrency-safe manner
+func (h *Health) safeDeleteState(name string) {
 	h.statesLock.Lock()
 	defer h.statesLock.Unlock()
 
-	h.states[stateEntry.Name] = *stateEntry
+	delete(h.states, name)
+	delete(h.passed, name)
+	delete(h.histories, name)
+	delete(h.latencies, name)
+	delete(h.metrics, name)
 }
 
 // get all states in a concurrency-safe manner
@@ -332,33 +1577,221 @@ func (h *Health) safeGetStates() map[string]State {
 	return statesCopy
 }
 
-// if a status listener is attached
-func (h *Health) handleStatusListener(stateEntry *State) {
-	// get the previous state
+// marks the state recorded at checkTime as stale, unless the runner has been
+// stopped or a newer state has been recorded in the meantime
+func (h *Health) safeMarkStale(r *runner, checkTime time.Time) {
 	h.statesLock.Lock()
+	defer h.statesLock.Unlock()
+
+	select {
+	case <-r.stop:
+		return
+	default:
+	}
+
+	prevState, ok := h.states[r.cfg.Name]
+	if !ok || prevState.isStale() || !prevState.CheckTime.Equal(checkTime) {
+		return
+	}
+
+	stateEntry := prevState
+	stateEntry.Status = StatusStale
+	stateEntry.Err = ErrCheckStale.Error()
+	stateEntry.lastStatus = prevState.lastRunStatus()
+
+	h.Logger.WithFields(log.Fields{
+		"check": r.cfg.Name,
+		"fatal": r.cfg.Fatal,
+		"age":   r.clock.Since(checkTime),
+	}).Warn("healthcheck is stale")
+
+	h.states[stateEntry.Name] = stateEntry
+	h.notifyStale(&stateEntry)
+}
+
+// publishes the stale event and dispatches the stale status listeners (if any)
+func (h *Health) notifyStale(stateEntry *State) {
+	h.publish(EventStale, stateEntry.Name, stateEntry)
+
+	h.notifyListeners(func(l IStatusListener) {
+		if sl, ok := l.(IStaleStatusListener); ok {
+			sl.HealthCheckStale(stateEntry)
+		}
+	})
+}
+
+// records the (stale or skipped) state entry of a check that did not run;
+// the bookkeeping of the previous state is carried over. Caller must hold
+// statesLock
+func (h *Health) updateNotRunState(cfg *Config, stateEntry *State) {
 	prevState := h.states[stateEntry.Name]
-	h.statesLock.Unlock()
+
+	stateEntry.lastStatus = prevState.lastRunStatus()
+	stateEntry.RawStatus = stateEntry.Status
+	stateEntry.Details = prevState.Details
+	stateEntry.Latency = prevState.Latency
+	stateEntry.ContiguousFailures = prevState.ContiguousFailures
+	stateEntry.ContiguousSuccesses = prevState.ContiguousSuccesses
+	stateEntry.TimeOfFirstFailure = prevState.TimeOfFirstFailure
+
+	var failures int64
+	if prevState.RawStatus == StatusFailed {
+		failures = prevState.ContiguousFailures
+	}
+
+	stateEntry.NextCheckTime = stateEntry.CheckTime.Add(cfg.nextInterval(failures) + cfg.jitter())
+
+	h.states[stateEntry.Name] = *stateEntry
+	h.publish(EventResult, stateEntry.Name, stateEntry)
+
+	if stateEntry.isStale() && !prevState.isStale() {
+		h.notifyStale(stateEntry)
+	}
+}
+
+// returns the name of the failing check that prevents the given check from
+// running (if any)
+func (h *Health) safeGetRootCause(cfg *Config) string {
+	if len(cfg.DependsOn) == 0 {
+		return ""
+	}
+
+	h.statesLock.Lock()
+	defer h.statesLock.Unlock()
+
+	return rootCause(cfg, h.states)
+}
+
+// returns the name of the failing check that prevents the given check from
+// running (if any), according to the given states
+func rootCause(cfg *Config, states map[string]State) string {
+	for _, name := range cfg.DependsOn {
+		dep, ok := states[name]
+		if !ok {
+			continue
+		}
+
+		if dep.isFailure() {
+			return name
+		}
+
+		if dep.isSkipped() {
+			return dep.RootCause
+		}
+	}
+
+	return ""
+}
+
+// applies the configured failure/success thresholds to the new state entry,
+// carries over the failure bookkeeping from the previous state and dispatches
+// any status listeners
+func (h *Health) handleStatusListener(cfg *Config, prevState State, stateEntry *State) {
+	// compare against the status the check had before it went stale or was skipped
+	prevState.Status = prevState.lastRunStatus()
+
+	stateEntry.RawStatus = stateEntry.Status
 
 	// state is failure
 	if stateEntry.isFailure() {
-		if !prevState.isFailure() {
-			// new failure: previous state was ok
-			if h.StatusListener != nil {
-				go h.StatusListener.HealthCheckFailed(stateEntry)
-			}
+		stateEntry.ContiguousFailures = prevState.ContiguousFailures + 1
 
-			stateEntry.TimeOfFirstFailure = time.Now()
-		} else {
+		if prevState.ContiguousFailures > 0 {
 			// carry the time of first failure from the previous state
 			stateEntry.TimeOfFirstFailure = prevState.TimeOfFirstFailure
+		} else {
+			stateEntry.TimeOfFirstFailure = h.clock().Now()
 		}
-		stateEntry.ContiguousFailures = prevState.ContiguousFailures + 1
-	} else if prevState.isFailure() {
-		// recovery, previous state was failure
-		failureSeconds := time.Now().Sub(prevState.TimeOfFirstFailure).Seconds()
 
-		if h.StatusListener != nil {
-			go h.StatusListener.HealthCheckRecovered(stateEntry, prevState.ContiguousFailures, failureSeconds)
+		if !prevState.isFailure() {
+			if stateEntry.ContiguousFailures < int64(thresholdOrDefault(cfg.FailureThreshold)) {
+				// not enough failures in a row yet, keep reporting the previous status
+				stateEntry.Status = prevState.Status
+				if stateEntry.Status == "" {
+					stateEntry.Status = StatusOK
+				}
+			} else {
+				// new failure: previous state was ok
+				h.publish(EventFailed, stateEntry.Name, stateEntry)
+
+				h.notifyListeners(func(l IStatusListener) {
+					l.HealthCheckFailed(stateEntry)
+				})
+			}
+		}
+	} else {
+		stateEntry.ContiguousSuccesses = prevState.ContiguousSuccesses + 1
+
+		if prevState.isFailure() {
+			if stateEntry.ContiguousSuccesses < int64(thresholdOrDefault(cfg.SuccessThreshold)) {
+				// not enough successes in a row yet, the check remains failed
+				stateEntry.Status = StatusFailed
+				stateEntry.ContiguousFailures = prevState.ContiguousFailures
+				stateEntry.TimeOfFirstFailure = prevState.TimeOfFirstFailure
+			} else {
+				// recovery, previous state was failure
+				failureSeconds := h.clock().Since(prevState.TimeOfFirstFailure).Seconds()
+
+				h.publish(EventRecovered, stateEntry.Name, stateEntry)
+
+				h.notifyListeners(func(l IStatusListener) {
+					l.HealthCheckRecovered(stateEntry, prevState.ContiguousFailures, failureSeconds)
+				})
+			}
+		}
+	}
+
+	if stateEntry.isWarning() && !prevState.isWarning() {
+		h.publish(EventDegraded, stateEntry.Name, stateEntry)
+
+		h.notifyListeners(func(l IStatusListener) {
+			if dl, ok := l.(IDegradedStatusListener); ok {
+				dl.HealthCheckDegraded(stateEntry)
+			}
+		})
+	} else if prevState.isWarning() && stateEntry.Status == StatusOK {
+		h.notifyListeners(func(l IStatusListener) {
+			if dl, ok := l.(IDegradedStatusListener); ok {
+				dl.HealthCheckDegradationCleared(stateEntry)
+			}
+		})
+	}
+}
+
+// indicates whether the given slice contains the given string
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
 		}
 	}
+
+	return false
+}
+
+// returns the configured clock, or the system clock if none is set
+func (h *Health) clock() clock.Clock {
+	if h.Clock != nil {
+		return h.Clock
+	}
+
+	return clock.New()
+}
+
+// returns the configured aggregator, or the default one
+func (h *Health) aggregator() Aggregator {
+	if h.Aggregator != nil {
+		return h.Aggregator
+	}
+
+	return FatalAggregator{}
+}
+
+// thresholds below one are treated as one
+func thresholdOrDefault(threshold int) int {
+	if threshold < 1 {
+		return 1
+	}
+
+	return threshold
 }
@@ -4,32 +4,40 @@ import (
 	"fmt"
 	"time"
 
+	. "github.com/onsi/gomega"
+
 	"github.com/InVisionApp/go-health/v2/fakes"
 	"github.com/InVisionApp/go-logger"
 )
 
+// returns two passing, non-fatal checks named "foo" and "bar"
+func setupTestConfigs() []*Config {
+	testCheckInterval := time.Duration(10) * time.Millisecond
+
+	checker1 := &fakes.FakeICheckable{}
+	checker2 := &fakes.FakeICheckable{}
+
+	return []*Config{
+		{
+			Name:     "foo",
+			Checker:  checker1,
+			Interval: testCheckInterval,
+			Fatal:    false,
+		},
+		{
+			Name:     "bar",
+			Checker:  checker2,
+			Interval: testCheckInterval,
+			Fatal:    false,
+		},
+	}
+}
+
 func setupRunners(cfgs []*Config, logger log.Logger) (*Health, []*Config, error) {
 	h := New()
-	testCheckInterval := time.Duration(10) * time.Millisecond
 
 	if cfgs == nil {
-		checker1 := &fakes.FakeICheckable{}
-		checker2 := &fakes.FakeICheckable{}
-
-		cfgs = []*Config{
-			{
-				Name:     "foo",
-				Checker:  checker1,
-				Interval: testCheckInterval,
-				Fatal:    false,
-			},
-			{
-				Name:     "bar",
-				Checker:  checker2,
-				Interval: testCheckInterval,
-				Fatal:    false,
-			},
-		}
+		cfgs = setupTestConfigs()
 	}
 
 	if err := h.AddChecks(cfgs); err != nil {
@@ -53,3 +61,34 @@ func setupRunners(cfgs []*Config, logger log.Logger) (*Health, []*Config, error)
 
 	return h, cfgs, nil
 }
+
+// starts the given checks on a fake clock (the one already set on h, if any)
+// and waits until "waiters" timers are armed, ie. until the first runs have
+// been recorded (see "advance()")
+func setupFakeClockRunners(h *Health, cfgs []*Config, waiters int) *fakes.FakeClock {
+	clk, ok := h.Clock.(*fakes.FakeClock)
+	if !ok {
+		clk = fakes.NewFakeClock(time.Now())
+		h.Clock = clk
+	}
+
+	Expect(h.AddChecks(cfgs)).To(Succeed())
+	Expect(h.Start()).To(Succeed())
+
+	awaitWaiters(clk, waiters)
+
+	return clk
+}
+
+// advances the fake clock by d and waits until "waiters" timers are armed
+// again. Every runner (re)arms its timer (and its staleness timer, unless
+// "Config.MaxAge" is negative) once its run has been recorded, so all runs due
+// within d have completed when this returns.
+func advance(clk *fakes.FakeClock, d time.Duration, waiters int) {
+	clk.Advance(d)
+	awaitWaiters(clk, waiters)
+}
+
+func awaitWaiters(clk *fakes.FakeClock, waiters int) {
+	Eventually(clk.Waiters).Should(BeNumerically(">=", waiters))
+}
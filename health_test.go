@@ -1,8 +1,10 @@
 package health
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,6 +30,26 @@ func (mock *MockStatusListener) HealthCheckRecovered(entry *State, recordedFailu
 	testLogger.Debug(entry.Name, recordedFailures, failureDurationSeconds)
 }
 
+type MockDegradedStatusListener struct {
+	MockStatusListener
+}
+
+func (mock *MockDegradedStatusListener) HealthCheckDegraded(entry *State) {
+	testLogger.Debug("degraded", entry.Name)
+}
+
+func (mock *MockDegradedStatusListener) HealthCheckDegradationCleared(entry *State) {
+	testLogger.Debug("cleared", entry.Name)
+}
+
+type MockStaleStatusListener struct {
+	MockStatusListener
+}
+
+func (mock *MockStaleStatusListener) HealthCheckStale(entry *State) {
+	testLogger.Debug("stale", entry.Name)
+}
+
 // since we dont have before each in this testing framework...
 func setupNewTestHealth() *Health {
 	h := New()
@@ -68,13 +90,40 @@ func TestAddChecks(t *testing.T) {
 		Expect(len(h.configs)).To(Equal(1))
 	})
 
-	t.Run("Should error if healthcheck is already running", func(t *testing.T) {
+	t.Run("Should start the checks if healthcheck is already running", func(t *testing.T) {
+		h, _, err := setupRunners(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer h.Stop()
+
+		checker := &fakes.FakeICheckable{}
+		err = h.AddChecks([]*Config{
+			{
+				Name:     "baz",
+				Checker:  checker,
+				Interval: testCheckInterval,
+			},
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(h.configs)).To(Equal(3))
+		Expect(h.runners).To(HaveKey("baz"))
+
+		Eventually(h.safeGetStates).Should(HaveKey("baz"))
+		Expect(checker.StatusCallCount()).To(BeNumerically(">", 0))
+	})
+
+	t.Run("Should error if a check with the same name was already added", func(t *testing.T) {
 		h := setupNewTestHealth()
-		h.active.setTrue()
-		err := h.AddChecks([]*Config{})
+		err := h.AddChecks([]*Config{{Name: "foo"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = h.AddChecks([]*Config{{Name: "bar"}, {Name: "foo"}})
+		Expect(err).To(Equal(ErrDuplicateCheck))
+		Expect(len(h.configs)).To(Equal(1))
 
-		Expect(err).To(HaveOccurred())
-		Expect(err).To(Equal(ErrNoAddCfgWhenActive))
+		err = h.AddChecks([]*Config{{Name: "baz"}, {Name: "baz"}})
+		Expect(err).To(Equal(ErrDuplicateCheck))
+		Expect(len(h.configs)).To(Equal(1))
 	})
 
 	t.Run("Should not error if passed in empty config slice", func(t *testing.T) {
@@ -104,13 +153,181 @@ func TestAddCheck(t *testing.T) {
 		Expect(len(h.configs)).To(Equal(1))
 	})
 
-	t.Run("Should error if healthcheck is already running", func(t *testing.T) {
+	t.Run("Should start the check if healthcheck is already running", func(t *testing.T) {
+		h, _, err := setupRunners(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer h.Stop()
+
+		checker := &fakes.FakeICheckable{}
+		err = h.AddCheck(&Config{
+			Name:     "baz",
+			Checker:  checker,
+			Interval: testCheckInterval,
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(h.runners).To(HaveKey("baz"))
+
+		Eventually(h.safeGetStates).Should(HaveKey("baz"))
+		Expect(checker.StatusCallCount()).To(BeNumerically(">", 0))
+	})
+}
+
+func TestRemoveCheck(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Happy path", func(t *testing.T) {
+		foo := &fakes.FakeICheckable{}
+		bar := &fakes.FakeICheckable{}
+
 		h := setupNewTestHealth()
-		h.active.setTrue()
-		err := h.AddCheck(&Config{})
+		clk := setupFakeClockRunners(h, []*Config{
+			{Name: "foo", Checker: foo, Interval: testCheckInterval, MaxAge: -1},
+			{Name: "bar", Checker: bar, Interval: testCheckInterval, MaxAge: -1},
+		}, 2)
+		defer h.Stop()
+
+		Expect(h.safeGetStates()).To(HaveKey("foo"))
+
+		err := h.RemoveCheck("foo")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(h.Checks()).To(Equal([]string{"bar"}))
+		Expect(h.safeGetStates()).ToNot(HaveKey("foo"))
+
+		// The removed checker should no longer run or record state (wait for
+		// its runner to disarm its timer before moving the clock)
+		Eventually(clk.Waiters).Should(Equal(1))
+		advance(clk, testCheckInterval, 1)
+
+		Expect(foo.StatusCallCount()).To(Equal(1))
+		Expect(bar.StatusCallCount()).To(Equal(2))
+		Expect(h.safeGetStates()).ToNot(HaveKey("foo"))
+		Expect(h.safeGetStates()).To(HaveKey("bar"))
+
+		Expect(h.Stop()).To(Succeed())
+		Expect(h.runners).To(BeEmpty())
+	})
+
+	t.Run("Should remove a check from an inactive healthcheck", func(t *testing.T) {
+		h := setupNewTestHealth()
+		err := h.AddCheck(&Config{Name: "foo"})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = h.RemoveCheck("foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(h.configs).To(BeEmpty())
+	})
+
+	t.Run("Should error if the check does not exist", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		err := h.RemoveCheck("foo")
+		Expect(err).To(Equal(ErrCheckNotFound))
+	})
+
+	t.Run("Should error while other checks depend on the check", func(t *testing.T) {
+		h := setupNewTestHealth()
+		Expect(h.AddChecks([]*Config{
+			{Name: "net", Checker: &fakes.FakeICheckable{}, Interval: time.Hour},
+			{Name: "db", Checker: &fakes.FakeICheckable{}, Interval: time.Hour, DependsOn: []string{"net"}},
+		})).To(Succeed())
+
+		Expect(h.RemoveCheck("net")).To(Equal(ErrCheckHasDependents))
+		Expect(h.Start()).To(Succeed())
+		Expect(h.RemoveCheck("net")).To(Equal(ErrCheckHasDependents))
+		Expect(h.AddCheck(&Config{Name: "cache", Checker: &fakes.FakeICheckable{}, Interval: time.Hour})).To(Succeed())
+
+		// removing the dependent first frees the dependency
+		Expect(h.RemoveCheck("db")).To(Succeed())
+		Expect(h.RemoveCheck("net")).To(Succeed())
+
+		Expect(h.Stop()).To(Succeed())
+		Expect(h.Start()).To(Succeed())
+		Expect(h.Stop()).To(Succeed())
+	})
+}
+
+func TestStartEmpty(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should run checks added after starting without any", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, nil, 0)
+
+		Expect(h.AddCheck(&Config{Name: "foo", Checker: checker, Interval: testCheckInterval})).To(Succeed())
+
+		awaitWaiters(clk, 2)
+		Expect(checker.StatusCallCount()).To(Equal(1))
+		Expect(h.safeGetStates()).To(HaveKey("foo"))
+
+		advance(clk, testCheckInterval, 2)
+		Expect(checker.StatusCallCount()).To(Equal(2))
+
+		Expect(h.Stop()).To(Succeed())
+	})
+}
+
+func TestChecks(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return the names of the added checks, in order", func(t *testing.T) {
+		h := setupNewTestHealth()
+		Expect(h.Checks()).To(BeEmpty())
+
+		Expect(h.AddChecks([]*Config{{Name: "foo"}, {Name: "bar"}})).To(Succeed())
+		Expect(h.Checks()).To(Equal([]string{"foo", "bar"}))
+
+		Expect(h.RemoveCheck("foo")).To(Succeed())
+		Expect(h.Checks()).To(Equal([]string{"bar"}))
+	})
+}
+
+func TestReplaceCheck(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Happy path", func(t *testing.T) {
+		previous := &fakes.FakeICheckable{}
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, []*Config{
+			{Name: "foo", Checker: previous, Interval: testCheckInterval, MaxAge: -1},
+			{Name: "bar", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, MaxAge: -1},
+		}, 2)
+		defer h.Stop()
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturns(nil, errors.New("replaced"))
+
+		replacement := &Config{
+			Name:     "foo",
+			Checker:  checker,
+			Interval: testCheckInterval,
+			MaxAge:   -1,
+		}
+
+		err := h.ReplaceCheck(replacement)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(h.Checks()).To(Equal([]string{"foo", "bar"}))
 
-		Expect(err).To(HaveOccurred())
-		Expect(err).To(Equal(ErrNoAddCfgWhenActive))
+		// the replacement runs right away, while the clock stands still
+		Eventually(func() string { return h.safeGetStates()["foo"].Err }).Should(Equal("replaced"))
+
+		Expect(h.Stop()).To(Succeed())
+		Expect(checker.StatusCallCount()).To(Equal(1))
+		Expect(previous.StatusCallCount()).To(Equal(1))
+		Expect(h.configs[0]).To(BeIdenticalTo(replacement))
+		Expect(h.runners).To(BeEmpty())
+	})
+
+	t.Run("Should error if the check does not exist", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		err := h.ReplaceCheck(&Config{Name: "foo"})
+		Expect(err).To(Equal(ErrCheckNotFound))
 	})
 }
 
@@ -146,14 +363,9 @@ func TestFailed(t *testing.T) {
 				},
 			}
 
-			err := h.AddChecks(cfgs)
-			Expect(err).ToNot(HaveOccurred())
-
-			err = h.Start()
-			Expect(err).ToNot(HaveOccurred())
-
-			// More brittleness -- need to wait to ensure our checks have executed
-			time.Sleep(time.Duration(15) * time.Millisecond)
+			// wait for the check to have executed
+			setupFakeClockRunners(h, cfgs, 2)
+			defer h.Stop()
 
 			states, failed, err := h.State()
 			Expect(err).ToNot(HaveOccurred())
@@ -188,14 +400,9 @@ func TestFailed(t *testing.T) {
 			},
 		}
 
-		err := h.AddChecks(cfgs)
-		Expect(err).ToNot(HaveOccurred())
-
-		err = h.Start()
-		Expect(err).ToNot(HaveOccurred())
-
-		// More brittleness -- need to wait to ensure our checks have executed
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		// wait for the checks to have executed
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
 
 		states, failed, err := h.State()
 		Expect(err).ToNot(HaveOccurred())
@@ -207,31 +414,237 @@ func TestFailed(t *testing.T) {
 	})
 }
 
-func TestState(t *testing.T) {
+func TestStatus(t *testing.T) {
 	RegisterTestingT(t)
 
-	t.Run("Happy path", func(t *testing.T) {
+	t.Run("Should return ok if all checks pass", func(t *testing.T) {
 		h := setupNewTestHealth()
+		setupFakeClockRunners(h, setupTestConfigs(), 4)
+		defer h.Stop()
+
+		Expect(h.Status()).To(Equal(StatusOK))
+	})
+
+	t.Run("Should return degraded if a check reports a warning", func(t *testing.T) {
 		checker1 := &fakes.FakeICheckable{}
-		checker1.StatusReturns(nil, fmt.Errorf("things broke"))
+		checker2 := &fakes.FakeICheckable{}
+		checker2.StatusReturns(nil, &Warning{Err: errors.New("getting slow")})
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker1,
+				Interval: testCheckInterval,
+				Fatal:    true,
+			},
+			{
+				Name:     "bar",
+				Checker:  checker2,
+				Interval: testCheckInterval,
+				Fatal:    true,
+			},
+		}
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
+
+		states, failed, err := h.State()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeFalse())
+		Expect(states["bar"].Status).To(Equal(StatusWarn))
+		Expect(states["bar"].Err).To(Equal("getting slow"))
+		Expect(h.Status()).To(Equal(StatusDegraded))
+	})
+
+	t.Run("Should return failed if a fatal check fails, even if others warn", func(t *testing.T) {
+		checker1 := &fakes.FakeICheckable{}
+		checker1.StatusReturns(nil, errors.New("broken"))
+		checker2 := &fakes.FakeICheckable{}
+		checker2.StatusReturns(nil, &Warning{Err: errors.New("getting slow")})
 
 		cfgs := []*Config{
 			{
 				Name:     "foo",
 				Checker:  checker1,
 				Interval: testCheckInterval,
+				Fatal:    true,
+			},
+			{
+				Name:     "bar",
+				Checker:  checker2,
+				Interval: testCheckInterval,
 				Fatal:    false,
 			},
 		}
 
-		err := h.AddChecks(cfgs)
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
+
+		Expect(h.Status()).To(Equal(StatusFailed))
+	})
+}
+
+func TestStateFor(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should only report on the checks of the given group", func(t *testing.T) {
+		checker1 := &fakes.FakeICheckable{}
+		checker1.StatusReturns(nil, errors.New("db down"))
+		checker2 := &fakes.FakeICheckable{}
+
+		cfgs := []*Config{
+			{
+				Name:     "db",
+				Checker:  checker1,
+				Interval: testCheckInterval,
+				Fatal:    true,
+				Groups:   []string{GroupReadiness},
+			},
+			{
+				Name:     "deadlock",
+				Checker:  checker2,
+				Interval: testCheckInterval,
+				Fatal:    true,
+				Groups:   []string{GroupLiveness, GroupReadiness},
+			},
+		}
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
+
+		states, failed, err := h.StateFor(GroupLiveness)
 		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeFalse())
+		Expect(states).To(HaveKey("deadlock"))
+		Expect(states).ToNot(HaveKey("db"))
+		Expect(states["deadlock"].Groups).To(Equal([]string{GroupLiveness, GroupReadiness}))
 
-		err = h.Start()
+		states, failed, err = h.StateFor(GroupReadiness)
 		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeTrue())
+		Expect(states).To(HaveLen(2))
+
+		Expect(h.FailedFor(GroupLiveness)).To(BeFalse())
+		Expect(h.FailedFor(GroupReadiness)).To(BeTrue())
+		Expect(h.FailedFor("unknown")).To(BeFalse())
+	})
+
+	t.Run("Startup group should fail until every startup check has passed once", func(t *testing.T) {
+		checker1 := &fakes.FakeICheckable{}
+		checker1.StatusReturnsOnCall(0, nil, errors.New("not yet"))
+		checker1.StatusReturnsOnCall(1, nil, errors.New("not yet"))
+		checker1.StatusReturnsOnCall(3, nil, errors.New("broken again"))
+
+		cfgs := []*Config{
+			{
+				Name:     "migrations",
+				Checker:  checker1,
+				Interval: testCheckInterval,
+				Fatal:    true,
+				Groups:   []string{GroupStartup},
+			},
+		}
+
+		clk := fakes.NewFakeClock(time.Now())
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		Expect(h.AddChecks(cfgs)).To(Succeed())
+
+		// Nothing has run yet
+		Expect(h.FailedFor(GroupStartup)).To(BeTrue())
+
+		Expect(h.Start()).To(Succeed())
+		defer h.Stop()
+
+		// two failed runs
+		awaitWaiters(clk, 2)
+		Expect(h.FailedFor(GroupStartup)).To(BeTrue())
+
+		advance(clk, testCheckInterval, 2)
+		Expect(h.FailedFor(GroupStartup)).To(BeTrue())
+
+		// third run passes
+		advance(clk, testCheckInterval, 2)
+		Expect(h.FailedFor(GroupStartup)).To(BeFalse())
+
+		// fourth run fails again, startup remains complete
+		advance(clk, testCheckInterval, 2)
+		Expect(h.safeGetStates()["migrations"].Status).To(Equal(StatusFailed))
+		Expect(h.FailedFor(GroupStartup)).To(BeFalse())
+	})
+
+	t.Run("Startup group should not count failures held by the failure threshold as passes", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, errors.New("not yet"))
+
+		cfgs := []*Config{
+			{
+				Name:             "migrations",
+				Checker:          checker,
+				Interval:         time.Second,
+				MaxAge:           -1,
+				FailureThreshold: 3,
+				Fatal:            true,
+				Groups:           []string{GroupStartup},
+			},
+		}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 1)
+		defer h.Stop()
+
+		// the failure is held, but the check has not passed yet
+		Expect(h.safeGetStates()["migrations"].Status).To(Equal(StatusOK))
+		Expect(h.FailedFor(GroupStartup)).To(BeTrue())
+
+		advance(clk, time.Second, 1)
+		Expect(h.FailedFor(GroupStartup)).To(BeFalse())
+	})
+
+	t.Run("Startup group should wait for non-fatal startup checks too", func(t *testing.T) {
+		warmup := &fakes.FakeICheckable{}
+		warmup.StatusReturnsOnCall(0, nil, errors.New("cache is cold"))
+
+		cfgs := []*Config{
+			{Name: "migrations", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, MaxAge: -1, Fatal: true, Groups: []string{GroupStartup}},
+			{Name: "warmup", Checker: warmup, Interval: testCheckInterval, MaxAge: -1, Groups: []string{GroupStartup}},
+		}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		Expect(h.FailedFor(GroupStartup)).To(BeTrue())
+
+		advance(clk, testCheckInterval, 2)
+		Expect(h.FailedFor(GroupStartup)).To(BeFalse())
+	})
+}
+
+func TestState(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Happy path", func(t *testing.T) {
+		h := setupNewTestHealth()
+		checker1 := &fakes.FakeICheckable{}
+		checker1.StatusReturns(nil, fmt.Errorf("things broke"))
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker1,
+				Interval: testCheckInterval,
+				Fatal:    false,
+			},
+		}
 
-		// More brittleness -- need to wait to ensure our checks have executed
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		// wait for the check to have executed
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
 
 		states, failed, err := h.State()
 		Expect(err).ToNot(HaveOccurred())
@@ -263,14 +676,9 @@ func TestState(t *testing.T) {
 			},
 		}
 
-		err := h.AddChecks(cfgs)
-		Expect(err).ToNot(HaveOccurred())
-
-		err = h.Start()
-		Expect(err).ToNot(HaveOccurred())
-
-		// More brittleness -- need to wait to ensure our checks have executed
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		// wait for the checks to have executed
+		clk := setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
 
 		states, failed, err := h.State()
 		Expect(err).ToNot(HaveOccurred())
@@ -280,7 +688,7 @@ func TestState(t *testing.T) {
 
 		// And now, let's let it recover
 		checker1.StatusReturns(nil, nil)
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		advance(clk, testCheckInterval, 4)
 
 		statesRecov, failedRecov, errRecov := h.State()
 		Expect(errRecov).ToNot(HaveOccurred())
@@ -315,6 +723,9 @@ func TestStart(t *testing.T) {
 			},
 		}
 
+		clk := fakes.NewFakeClock(time.Now())
+		h.Clock = clk
+
 		err := h.AddChecks(cfgs)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -323,6 +734,7 @@ func TestStart(t *testing.T) {
 
 		err = h.Start()
 		Expect(err).ToNot(HaveOccurred())
+
 		// Correct number of runners were created
 		Expect(len(h.runners)).To(Equal(2))
 
@@ -331,16 +743,17 @@ func TestStart(t *testing.T) {
 			Expect(h.runners).To(HaveKey(v.Name))
 		}
 
-		// This is pretty brittle - will update if this is causing random test failures
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		// Let both runners run right away and once more after their interval
+		awaitWaiters(clk, 4)
+		advance(clk, testCheckInterval, 4)
 
 		// Both runners should've ran
 		Expect(checker1.StatusCallCount()).To(Equal(2), "Checker should have been executed")
 		Expect(checker2.StatusCallCount()).To(Equal(2), "Checker should have been executed")
 
 		// Both runners should've recorded their state
-		Expect(h.states).To(HaveKey("foo"))
-		Expect(h.states).To(HaveKey("bar"))
+		Expect(h.safeGetStates()).To(HaveKey("foo"))
+		Expect(h.safeGetStates()).To(HaveKey("bar"))
 
 		// Ensure that logger was hit as expected
 		Expect(testLogger.CallCount()).To(Equal(2))
@@ -369,21 +782,18 @@ func TestStop(t *testing.T) {
 
 	t.Run("Happy path", func(t *testing.T) {
 		testLogger := testlog.New()
-		h, cfgs, err := setupRunners(nil, testLogger)
+		cfgs := setupTestConfigs()
 
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
+		h := setupNewTestHealth()
+		h.Logger = testLogger
+		setupFakeClockRunners(h, cfgs, 4)
 
-		// A bit brittle, but it'll do
-		time.Sleep(time.Duration(15) * time.Millisecond)
-		Expect(len(h.states)).To(Equal(2))
+		Expect(h.safeGetStates()).To(HaveLen(2))
 
-		err = h.Stop()
+		// Stop waits for the goroutines to exit
+		err := h.Stop()
 		Expect(err).ToNot(HaveOccurred())
 
-		// Wait a bit to ensure goroutines have exited
-		time.Sleep(15 * time.Millisecond)
-
 		// Runners map should be reset
 		Expect(h.runners).To(BeEmpty())
 
@@ -398,7 +808,7 @@ func TestStop(t *testing.T) {
 		}
 
 		// Expect state map to be reset
-		Expect(len(h.states)).To(Equal(0))
+		Expect(h.safeGetStates()).To(BeEmpty())
 	})
 
 	t.Run("Should error if healthcheck is not running", func(t *testing.T) {
@@ -412,31 +822,176 @@ func TestStop(t *testing.T) {
 		err = h.Stop()
 		Expect(err).To(Equal(ErrAlreadyStopped))
 	})
+
+	t.Run("Should be able to start again after being stopped", func(t *testing.T) {
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, setupTestConfigs(), 4)
+
+		Expect(h.Stop()).To(Succeed())
+		Expect(h.active.val()).To(BeFalse())
+		Expect(clk.Waiters()).To(BeZero())
+
+		Expect(h.Start()).To(Succeed())
+		Expect(h.active.val()).To(BeTrue())
+		Expect(h.runners).To(HaveLen(2))
+
+		awaitWaiters(clk, 4)
+		Expect(h.safeGetStates()).To(HaveLen(2))
+
+		Expect(h.Stop()).To(Succeed())
+	})
+
+	t.Run("Should wait for in-flight checks and hooks", func(t *testing.T) {
+		checkStarted := make(chan struct{})
+		checkRelease := make(chan struct{})
+
+		blocking := &fakes.FakeICheckable{}
+		blocking.StatusStub = func() (interface{}, error) {
+			close(checkStarted)
+			<-checkRelease
+			return nil, nil
+		}
+
+		hookStarted := make(chan struct{})
+		hookRelease := make(chan struct{})
+
+		var h *Health
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  &fakes.FakeICheckable{},
+				Interval: time.Hour,
+				OnComplete: func(state *State) {
+					close(hookStarted)
+					<-hookRelease
+
+					// hooks may call back into the healthcheck while it stops
+					h.History("foo")
+				},
+			},
+			{
+				Name:     "bar",
+				Checker:  blocking,
+				Interval: time.Hour,
+			},
+		}
+
+		h = setupNewTestHealth()
+		Expect(h.AddChecks(cfgs)).To(Succeed())
+		Expect(h.Start()).To(Succeed())
+
+		<-checkStarted
+		<-hookStarted
+
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- h.Stop()
+		}()
+
+		Consistently(stopped).ShouldNot(Receive())
+
+		close(hookRelease)
+		Consistently(stopped).ShouldNot(Receive())
+
+		close(checkRelease)
+		Eventually(stopped).Should(Receive(BeNil()))
+		Expect(h.safeGetStates()).To(BeEmpty())
+	})
+
+	t.Run("Should give up waiting once the context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusStub = func() (interface{}, error) {
+			<-release
+			return nil, nil
+		}
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker,
+				Interval: testCheckInterval,
+			},
+		}
+
+		h, _, err := setupRunners(cfgs, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(h).ToNot(BeNil())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		Expect(h.StopContext(ctx)).To(Equal(context.DeadlineExceeded))
+		Expect(h.active.val()).To(BeFalse())
+		Expect(h.runners).To(BeEmpty())
+	})
+
+	t.Run("Should cancel in-flight context aware checks right away", func(t *testing.T) {
+		started := make(chan struct{})
+
+		checker := &fakes.FakeIContextCheckable{}
+		checker.StatusContextStub = func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker,
+				Interval: time.Hour,
+			},
+		}
+
+		h := setupNewTestHealth()
+		Expect(h.AddChecks(cfgs)).To(Succeed())
+		Expect(h.Start()).To(Succeed())
+
+		<-started
+
+		// the cancelled check lets the runner exit, so no deadline is needed
+		Expect(h.StopContext(context.Background())).To(Succeed())
+		Expect(h.safeGetStates()).To(BeEmpty())
+	})
+
+	t.Run("Should keep the states if PreserveStates is set", func(t *testing.T) {
+		h := setupNewTestHealth()
+		h.PreserveStates = true
+
+		setupFakeClockRunners(h, setupTestConfigs(), 4)
+
+		Expect(h.Stop()).To(Succeed())
+		Expect(h.safeGetStates()).To(HaveLen(2))
+	})
 }
 
 func TestStartRunner(t *testing.T) {
 	RegisterTestingT(t)
 
 	t.Run("Happy path - checkers do not fail", func(t *testing.T) {
-		h, cfgs, err := setupRunners(nil, nil)
-
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
+		cfgs := setupTestConfigs()
 
-		// Brittle...
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
 
-		// Did the ticker fire and create a state entry?
+		// Did the runner create a state entry?
+		states := h.safeGetStates()
 		for _, c := range cfgs {
-			Expect(h.states).To(HaveKey(c.Name))
-			Expect(h.states[c.Name].Status).To(Equal("ok"))
+			Expect(states).To(HaveKey(c.Name))
+			Expect(states[c.Name].Status).To(Equal("ok"))
 		}
 
 		// Since nothing has failed, healthcheck should _not_ be in failed state
 		Expect(h.Failed()).To(BeFalse())
 	})
 
-	t.Run("Happy path - no checkers is noop", func(t *testing.T) {
+	t.Run("Happy path - no checkers still starts", func(t *testing.T) {
 		h := New()
 
 		Expect(h).ToNot(BeNil())
@@ -444,7 +999,8 @@ func TestStartRunner(t *testing.T) {
 		err := h.Start()
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(h.active.val()).To(BeFalse())
+		Expect(h.active.val()).To(BeTrue())
+		Expect(h.Stop()).To(Succeed())
 	})
 
 	t.Run("Happy path - 1 checker fails (non-fatal)", func(t *testing.T) {
@@ -467,25 +1023,23 @@ func TestStartRunner(t *testing.T) {
 				Fatal:    false,
 			},
 		}
-		h, _, err := setupRunners(cfgs, nil)
 
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
-
-		// Brittle...
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
 
-		// Did the ticker fire and create a state entry?
+		// Did the runner create a state entry?
+		states := h.safeGetStates()
 		for _, c := range cfgs {
-			Expect(h.states).To(HaveKey(c.Name))
+			Expect(states).To(HaveKey(c.Name))
 		}
 
 		// First checker should've succeeded
-		Expect(h.states[cfgs[0].Name].Status).To(Equal("ok"))
+		Expect(states[cfgs[0].Name].Status).To(Equal("ok"))
 
 		// Second checker should've failed
-		Expect(h.states[cfgs[1].Name].Status).To(Equal("failed"))
-		Expect(h.states[cfgs[1].Name].Err).To(Equal(checker2Error.Error()))
+		Expect(states[cfgs[1].Name].Status).To(Equal("failed"))
+		Expect(states[cfgs[1].Name].Err).To(Equal(checker2Error.Error()))
 
 		// Since nothing has failed, healthcheck should _not_ be in failed state
 		Expect(h.Failed()).To(BeFalse())
@@ -511,25 +1065,23 @@ func TestStartRunner(t *testing.T) {
 				Fatal:    true,
 			},
 		}
-		h, _, err := setupRunners(cfgs, nil)
-
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
 
-		// Brittle...
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
 
-		// Did the ticker fire and create a state entry?
+		// Did the runner create a state entry?
+		states := h.safeGetStates()
 		for _, c := range cfgs {
-			Expect(h.states).To(HaveKey(c.Name))
+			Expect(states).To(HaveKey(c.Name))
 		}
 
 		// First checker should've succeeded
-		Expect(h.states[cfgs[0].Name].Status).To(Equal("ok"))
+		Expect(states[cfgs[0].Name].Status).To(Equal("ok"))
 
 		// Second checker should've failed
-		Expect(h.states[cfgs[1].Name].Status).To(Equal("failed"))
-		Expect(h.states[cfgs[1].Name].Err).To(Equal(checker2Err.Error()))
+		Expect(states[cfgs[1].Name].Status).To(Equal("failed"))
+		Expect(states[cfgs[1].Name].Err).To(Equal(checker2Err.Error()))
 
 		// Since second checker has failed fatally, global healthcheck state should be failed as well
 		Expect(h.Failed()).To(BeTrue())
@@ -538,11 +1090,9 @@ func TestStartRunner(t *testing.T) {
 	t.Run("Should call the OnComplete hook when the health check is complete", func(t *testing.T) {
 		checker := &fakes.FakeICheckable{}
 
-		var calledState *State
-		called := false
+		completed := make(chan *State, 1)
 		completeFunc := func(state *State) {
-			called = true
-			calledState = state
+			completed <- state
 		}
 
 		cfgs := []*Config{
@@ -555,19 +1105,16 @@ func TestStartRunner(t *testing.T) {
 			},
 		}
 
-		h, _, err := setupRunners(cfgs, nil)
-
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
-
-		// Brittle...
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
 
-		// Did the ticker fire and create a state entry?
-		Expect(h.states).To(HaveKey(cfgs[0].Name))
+		// Did the runner create a state entry?
+		Expect(h.safeGetStates()).To(HaveKey(cfgs[0].Name))
 
 		// Hook should have been called
-		Expect(called).To(BeTrue())
+		var calledState *State
+		Eventually(completed).Should(Receive(&calledState))
 		Expect(calledState).ToNot(BeNil())
 		Expect(calledState.Name).To(Equal(cfgs[0].Name))
 		Expect(calledState.Status).To(Equal("ok"))
@@ -576,15 +1123,13 @@ func TestStartRunner(t *testing.T) {
 	t.Run("Modifying the state in the OnComplete hook should not modify the one saved in the states map", func(t *testing.T) {
 		checker := &fakes.FakeICheckable{}
 
-		var calledState *State
-		called := false
+		completed := make(chan *State, 1)
 		changedName := "Guybrush Threepwood"
 		changedStatus := "never"
 		completeFunc := func(state *State) {
-			called = true
 			state.Name = changedName
 			state.Status = changedStatus
-			calledState = state
+			completed <- state
 		}
 
 		cfgs := []*Config{
@@ -597,120 +1142,1256 @@ func TestStartRunner(t *testing.T) {
 			},
 		}
 
-		h, _, err := setupRunners(cfgs, nil)
-
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
-
-		// Brittle...
-		time.Sleep(time.Duration(15) * time.Millisecond)
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
 
-		// Did the ticker fire and create a state entry?
-		Expect(h.states).To(HaveKey(cfgs[0].Name))
+		// Did the runner create a state entry?
+		Expect(h.safeGetStates()).To(HaveKey(cfgs[0].Name))
 
 		// Hook should have been called
-		Expect(called).To(BeTrue())
+		var calledState *State
+		Eventually(completed).Should(Receive(&calledState))
 		Expect(calledState).ToNot(BeNil())
 
 		//changed status in OnComplete should not affect internal states map
 		Expect(calledState.Name).To(Equal(changedName))
 		Expect(calledState.Status).To(Equal(changedStatus))
-		Expect(h.states[cfgs[0].Name].Name).To(Equal(cfgs[0].Name))
-		Expect(h.states[cfgs[0].Name].Status).To(Equal("ok"))
+		Expect(h.safeGetStates()[cfgs[0].Name].Name).To(Equal(cfgs[0].Name))
+		Expect(h.safeGetStates()[cfgs[0].Name].Status).To(Equal("ok"))
 	})
-}
 
-func TestStatusListenerOnFail(t *testing.T) {
-	RegisterTestingT(t)
+	t.Run("Should fail a check that does not complete within its timeout", func(t *testing.T) {
+		release := make(chan struct{})
 
-	t.Run("happy path - a health check fails and failure is reported, no previous states", func(tt *testing.T) {
-		testLogger = testlog.New()
 		checker := &fakes.FakeICheckable{}
-		checkErr := errors.New("check error")
-		checker.StatusReturns(nil, checkErr)
+		checker.StatusStub = func() (interface{}, error) {
+			<-release
+			return nil, nil
+		}
+
 		cfgs := []*Config{
 			{
-				Name:     "FOOCHECK",
+				Name:     "SlowCheck",
 				Checker:  checker,
 				Interval: testCheckInterval,
-				Fatal:    false,
+				Timeout:  time.Duration(5) * time.Millisecond,
+				Fatal:    true,
 			},
 		}
-		h, _, err := setupRunners(cfgs, nil)
 
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
+		// the timeout is enforced by the context of the run, not the clock
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+		defer close(release)
+
+		states := h.safeGetStates()
+		Expect(states).To(HaveKey(cfgs[0].Name))
+		Expect(states[cfgs[0].Name].Status).To(Equal("failed"))
+		Expect(states[cfgs[0].Name].Err).To(Equal(ErrCheckTimeout.Error()))
+		Expect(h.Failed()).To(BeTrue())
+	})
 
-		// add listener
-		h.StatusListener = &MockStatusListener{}
+	t.Run("Should delay the first run by the initial delay", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
 
-		// let the health check run once
-		time.Sleep(5 * time.Millisecond)
+		cfgs := []*Config{
+			{
+				Name:         "DelayedCheck",
+				Checker:      checker,
+				Interval:     testCheckInterval,
+				InitialDelay: time.Duration(20) * time.Millisecond,
+			},
+		}
 
-		Expect(string(testLogger.Bytes())).To(ContainSubstring("FOOCHECK"))
+		// only the initial delay is armed
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 1)
+		defer h.Stop()
+
+		clk.Advance(time.Duration(5) * time.Millisecond)
+		Expect(checker.StatusCallCount()).To(Equal(0))
+
+		advance(clk, time.Duration(15)*time.Millisecond, 2)
+		Expect(checker.StatusCallCount()).To(Equal(1))
 	})
 
-	t.Run("happy path - a health check fails and failure is reported, 3 previous states", func(tt *testing.T) {
+	t.Run("Should back off while the check keeps failing", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturns(nil, errors.New("things broke"))
+
+		cfgs := []*Config{
+			{
+				Name:     "BackoffCheck",
+				Checker:  checker,
+				Interval: time.Duration(5) * time.Millisecond,
+				FailureBackoff: &Backoff{
+					Multiplier: 10,
+				},
+			},
+		}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		// runs at 0, 5 and 55ms
+		advance(clk, time.Duration(5)*time.Millisecond, 2)
+		Expect(checker.StatusCallCount()).To(Equal(2))
+
+		state := h.safeGetStates()[cfgs[0].Name]
+		Expect(state.NextCheckTime.Sub(state.CheckTime)).To(Equal(50 * time.Millisecond))
+
+		clk.Advance(time.Duration(49) * time.Millisecond)
+		Expect(checker.StatusCallCount()).To(Equal(2))
+
+		advance(clk, time.Millisecond, 2)
+		Expect(checker.StatusCallCount()).To(Equal(3))
+	})
+
+	t.Run("Should switch between the interval and the failing interval", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, errors.New("things broke"))
+		checker.StatusReturnsOnCall(1, nil, errors.New("things broke"))
+
+		cfgs := []*Config{
+			{
+				Name:            "FailingIntervalCheck",
+				Checker:         checker,
+				Interval:        time.Hour,
+				FailingInterval: time.Duration(5) * time.Millisecond,
+			},
+		}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		// runs at 0, 5 and 10ms, then waits for an hour
+		advance(clk, time.Duration(5)*time.Millisecond, 2)
+		advance(clk, time.Duration(5)*time.Millisecond, 2)
+		Expect(checker.StatusCallCount()).To(Equal(3))
+
+		state := h.safeGetStates()[cfgs[0].Name]
+		Expect(state.Status).To(Equal(StatusOK))
+		Expect(state.NextCheckTime.Sub(state.CheckTime)).To(Equal(time.Hour))
+
+		clk.Advance(time.Duration(5) * time.Millisecond)
+		Expect(checker.StatusCallCount()).To(Equal(3))
+	})
+
+	t.Run("Should recover from a panicking checker and keep running it", func(t *testing.T) {
 		testLogger = testlog.New()
+
 		checker := &fakes.FakeICheckable{}
-		checkErr := errors.New("check error")
-		checker.StatusReturnsOnCall(3, nil, checkErr)
+		checker.StatusStub = func() (interface{}, error) {
+			panic("boom")
+		}
+
 		cfgs := []*Config{
 			{
-				Name:     "FOOCHECK",
+				Name:     "PanicCheck",
 				Checker:  checker,
 				Interval: testCheckInterval,
-				Fatal:    false,
+				Fatal:    true,
 			},
 		}
-		h, _, err := setupRunners(cfgs, nil)
-
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
 
-		// add listener
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
 		h.StatusListener = &MockStatusListener{}
 
-		// let the health check run once
-		time.Sleep(35 * time.Millisecond)
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
 
-		Expect(string(testLogger.Bytes())).To(ContainSubstring("FOOCHECK"))
+		advance(clk, testCheckInterval, 2)
+		Expect(checker.StatusCallCount()).To(Equal(2))
+
+		state := h.safeGetStates()[cfgs[0].Name]
+		Expect(state.Status).To(Equal(StatusFailed))
+		Expect(state.Err).To(Equal("Health check panicked: boom"))
+		Expect(state.Details).To(HaveKeyWithValue("panic", "boom"))
+		Expect(state.Details).To(HaveKeyWithValue("stack", ContainSubstring("checkStatus")))
+		Expect(h.Failed()).To(BeTrue())
+
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("PanicCheck"))
+	})
+
+	t.Run("Should pass a context with a deadline to context aware checkers", func(t *testing.T) {
+		checker := &fakes.FakeIContextCheckable{}
+
+		cfgs := []*Config{
+			{
+				Name:     "ContextCheck",
+				Checker:  checker,
+				Interval: testCheckInterval,
+				Timeout:  time.Second,
+			},
+		}
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		Expect(checker.StatusContextCallCount()).To(Equal(1))
+		Expect(checker.StatusCallCount()).To(Equal(0))
+
+		_, hasDeadline := checker.StatusContextArgsForCall(0).Deadline()
+		Expect(hasDeadline).To(BeTrue())
 	})
 }
 
-func TestStatusListenerOnRecover(t *testing.T) {
+func TestThresholds(t *testing.T) {
 	RegisterTestingT(t)
 
-	t.Run("happy path - health check recovers after 3 errors", func(tt *testing.T) {
-		testLogger = testlog.New()
+	t.Run("Should only mark a check as failed after FailureThreshold failures in a row", func(t *testing.T) {
 		checker := &fakes.FakeICheckable{}
-		checkErr := errors.New("check error")
-		checker.StatusReturnsOnCall(0, nil, checkErr)
-		checker.StatusReturnsOnCall(1, nil, checkErr)
-		checker.StatusReturnsOnCall(2, nil, checkErr)
+		checker.StatusReturns(nil, errors.New("things broke"))
 
 		cfgs := []*Config{
 			{
-				Name:     "FOOCHECK",
-				Checker:  checker,
-				Interval: testCheckInterval,
-				Fatal:    false,
+				Name:             "foo",
+				Checker:          checker,
+				Interval:         testCheckInterval,
+				Fatal:            true,
+				FailureThreshold: 3,
 			},
 		}
-		h, _, err := setupRunners(cfgs, nil)
 
-		Expect(err).ToNot(HaveOccurred())
-		Expect(h).ToNot(BeNil())
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		state := h.safeGetStates()["foo"]
+		Expect(state.Status).To(Equal(StatusOK))
+		Expect(state.RawStatus).To(Equal(StatusFailed))
+		Expect(state.Err).To(Equal("things broke"))
+		Expect(state.ContiguousFailures).To(Equal(int64(1)))
+		Expect(h.Failed()).To(BeFalse())
 
-		// add listener
-		h.StatusListener = &MockStatusListener{}
+		// let it run two more times
+		advance(clk, testCheckInterval, 2)
+		Expect(h.Failed()).To(BeFalse())
 
-		// let the health check recover and run a few more times
-		time.Sleep(55 * time.Millisecond)
+		advance(clk, testCheckInterval, 2)
 
-		// check name, number of total failures, number of seconds in failure
-		testStr := "FOOCHECK3 0.03"
+		state = h.safeGetStates()["foo"]
+		Expect(state.Status).To(Equal(StatusFailed))
+		Expect(state.ContiguousFailures).To(Equal(int64(3)))
+		Expect(h.Failed()).To(BeTrue())
+	})
 
-		Expect(string(testLogger.Bytes())).To(ContainSubstring(testStr))
+	t.Run("Should only recover a check after SuccessThreshold successes in a row", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, errors.New("things broke"))
+
+		cfgs := []*Config{
+			{
+				Name:             "foo",
+				Checker:          checker,
+				Interval:         testCheckInterval,
+				Fatal:            true,
+				SuccessThreshold: 3,
+			},
+		}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		// let it run a second time
+		advance(clk, testCheckInterval, 2)
+
+		state := h.safeGetStates()["foo"]
+		Expect(state.Status).To(Equal(StatusFailed))
+		Expect(state.RawStatus).To(Equal(StatusOK))
+		Expect(state.ContiguousSuccesses).To(Equal(int64(1)))
+		Expect(state.ContiguousFailures).To(Equal(int64(1)))
+		Expect(h.Failed()).To(BeTrue())
+
+		// let it run two more times
+		advance(clk, testCheckInterval, 2)
+		Expect(h.Failed()).To(BeTrue())
+
+		advance(clk, testCheckInterval, 2)
+
+		state = h.safeGetStates()["foo"]
+		Expect(state.Status).To(Equal(StatusOK))
+		Expect(state.ContiguousFailures).To(Equal(int64(0)))
+		Expect(h.Failed()).To(BeFalse())
+	})
+}
+
+func TestHistory(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should keep the last HistorySize results of a check", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, errors.New("things broke"))
+
+		cfgs := []*Config{
+			{
+				Name:        "foo",
+				Checker:     checker,
+				Interval:    testCheckInterval,
+				HistorySize: 3,
+			},
+		}
+
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		// let it run a second time
+		advance(clk, testCheckInterval, 2)
+
+		entries, err := h.History("foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Status).To(Equal(StatusFailed))
+		Expect(entries[0].Err).To(Equal("things broke"))
+		Expect(entries[1].Status).To(Equal(StatusOK))
+		Expect(entries[1].CheckTime).To(Equal(entries[0].CheckTime.Add(testCheckInterval)))
+
+		// let it run two more times, pushing out the failure
+		advance(clk, testCheckInterval, 2)
+		advance(clk, testCheckInterval, 2)
+
+		entries, err = h.History("foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(3))
+
+		for _, e := range entries {
+			Expect(e.Status).To(Equal(StatusOK))
+		}
+	})
+
+	t.Run("Should return an empty history if it is disabled", func(t *testing.T) {
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, setupTestConfigs(), 4)
+		defer h.Stop()
+
+		entries, err := h.History("foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	t.Run("Should error if the check does not exist", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		_, err := h.History("foo")
+		Expect(err).To(Equal(ErrCheckNotFound))
+	})
+}
+
+func TestLatency(t *testing.T) {
+	RegisterTestingT(t)
+
+	// the checker takes 2ms on the fake clock
+	slowChecker := func(clk *fakes.FakeClock) *fakes.FakeICheckable {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusStub = func() (interface{}, error) {
+			clk.Advance(2 * time.Millisecond)
+			return nil, nil
+		}
+
+		return checker
+	}
+
+	t.Run("Should record the duration and latency stats of a check", func(t *testing.T) {
+		clk := fakes.NewFakeClock(time.Now())
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  slowChecker(clk),
+				Interval: testCheckInterval,
+			},
+		}
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		state := h.safeGetStates()["foo"]
+		Expect(state.Status).To(Equal(StatusOK))
+		Expect(state.Duration).To(Equal(2 * time.Millisecond))
+		Expect(state.Latency).ToNot(BeNil())
+		Expect(state.Latency.Min).To(Equal(2 * time.Millisecond))
+		Expect(state.Latency.Max).To(BeNumerically(">=", state.Latency.Min))
+	})
+
+	t.Run("Should mark a check exceeding its latency threshold as degraded", func(t *testing.T) {
+		clk := fakes.NewFakeClock(time.Now())
+
+		cfgs := []*Config{
+			{
+				Name:             "foo",
+				Checker:          slowChecker(clk),
+				Interval:         testCheckInterval,
+				Fatal:            true,
+				LatencyThreshold: time.Millisecond,
+			},
+		}
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		state := h.safeGetStates()["foo"]
+		Expect(state.Status).To(Equal(StatusWarn))
+		Expect(state.Err).To(ContainSubstring("exceeding the latency threshold of 1ms"))
+		Expect(h.Status()).To(Equal(StatusDegraded))
+	})
+
+	t.Run("Should mark a check exceeding its latency threshold as failed if configured", func(t *testing.T) {
+		clk := fakes.NewFakeClock(time.Now())
+
+		cfgs := []*Config{
+			{
+				Name:             "foo",
+				Checker:          slowChecker(clk),
+				Interval:         testCheckInterval,
+				Fatal:            true,
+				LatencyThreshold: time.Millisecond,
+				LatencyFailure:   true,
+			},
+		}
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		Expect(h.safeGetStates()["foo"].Status).To(Equal(StatusFailed))
+		Expect(h.Failed()).To(BeTrue())
+	})
+}
+
+func TestStatusListenerOnFail(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("happy path - a health check fails and failure is reported, no previous states", func(tt *testing.T) {
+		testLogger = testlog.New()
+		checker := &fakes.FakeICheckable{}
+		checkErr := errors.New("check error")
+		checker.StatusReturns(nil, checkErr)
+		cfgs := []*Config{
+			{
+				Name:     "FOOCHECK",
+				Checker:  checker,
+				Interval: testCheckInterval,
+				Fatal:    false,
+			},
+		}
+
+		// add listener
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
+		h.StatusListener = &MockStatusListener{}
+
+		// let the health check run once
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("FOOCHECK"))
+	})
+
+	t.Run("happy path - a health check fails and failure is reported, 3 previous states", func(tt *testing.T) {
+		testLogger = testlog.New()
+		checker := &fakes.FakeICheckable{}
+		checkErr := errors.New("check error")
+		checker.StatusReturnsOnCall(3, nil, checkErr)
+		cfgs := []*Config{
+			{
+				Name:     "FOOCHECK",
+				Checker:  checker,
+				Interval: testCheckInterval,
+				Fatal:    false,
+			},
+		}
+
+		// add listener
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
+		h.StatusListener = &MockStatusListener{}
+
+		// let the health check pass three times, then fail
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		advance(clk, testCheckInterval, 2)
+		advance(clk, testCheckInterval, 2)
+		Expect(string(testLogger.Bytes())).ToNot(ContainSubstring("FOOCHECK"))
+
+		advance(clk, testCheckInterval, 2)
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("FOOCHECK"))
+	})
+}
+
+func TestStatusListenerOnRecover(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("happy path - health check recovers after 3 errors", func(tt *testing.T) {
+		testLogger = testlog.New()
+		checker := &fakes.FakeICheckable{}
+		checkErr := errors.New("check error")
+		checker.StatusReturnsOnCall(0, nil, checkErr)
+		checker.StatusReturnsOnCall(1, nil, checkErr)
+		checker.StatusReturnsOnCall(2, nil, checkErr)
+
+		cfgs := []*Config{
+			{
+				Name:     "FOOCHECK",
+				Checker:  checker,
+				Interval: testCheckInterval,
+				MaxAge:   -1,
+				Fatal:    false,
+			},
+		}
+
+		clk := fakes.NewFakeClock(time.Now())
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		h.SyncStatusListeners = true
+		h.StatusListener = &MockStatusListener{}
+
+		err := h.AddChecks(cfgs)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = h.Start()
+		Expect(err).ToNot(HaveOccurred())
+
+		defer h.Stop()
+
+		// fail 3 times, then recover
+		for i := 0; i < 3; i++ {
+			clk.BlockUntil(1)
+			clk.Advance(testCheckInterval)
+		}
+
+		// wait for the recovered run to be recorded
+		clk.BlockUntil(1)
+
+		Expect(checker.StatusCallCount()).To(Equal(4))
+
+		// check name, number of total failures, number of seconds in failure
+		testStr := "FOOCHECK3 0.03"
+
+		Expect(string(testLogger.Bytes())).To(ContainSubstring(testStr))
+
+		state := h.safeGetStates()["FOOCHECK"]
+		Expect(state.Status).To(Equal(StatusOK))
+		Expect(state.CheckTime).To(Equal(clk.Now()))
+	})
+}
+
+func TestStatusListenerOnDegraded(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("happy path - degradation and its clearing are reported", func(tt *testing.T) {
+		testLogger = testlog.New()
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, &Warning{Err: errors.New("check warning")})
+
+		cfgs := []*Config{
+			{
+				Name:     "FOOCHECK",
+				Checker:  checker,
+				Interval: testCheckInterval,
+				Fatal:    false,
+			},
+		}
+
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
+		h.StatusListener = &MockDegradedStatusListener{}
+
+		clk := setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("degradedFOOCHECK"))
+
+		// let the health check clear
+		advance(clk, testCheckInterval, 2)
+
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("clearedFOOCHECK"))
+	})
+}
+
+func TestRunNow(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should run the check immediately and return the fresh state", func(t *testing.T) {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturns(nil, errors.New("things broke"))
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker,
+				Interval: time.Hour,
+			},
+		}
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 2)
+		defer h.Stop()
+
+		Expect(checker.StatusCallCount()).To(Equal(1))
+
+		state, err := h.RunNow("foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checker.StatusCallCount()).To(Equal(2))
+		Expect(state.NextCheckTime).To(Equal(state.CheckTime.Add(time.Hour)))
+		Expect(state.Status).To(Equal(StatusFailed))
+		Expect(state.ContiguousFailures).To(Equal(int64(2)))
+		Expect(h.safeGetStates()["foo"].ContiguousFailures).To(Equal(int64(2)))
+	})
+
+	t.Run("Should coalesce with an in-flight run", func(t *testing.T) {
+		release := make(chan struct{})
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusStub = func() (interface{}, error) {
+			<-release
+			return nil, nil
+		}
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker,
+				Interval: time.Hour,
+			},
+		}
+
+		h, _, err := setupRunners(cfgs, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer h.Stop()
+
+		h.runnersLock.Lock()
+		r := h.runners["foo"]
+		h.runnersLock.Unlock()
+
+		// the number of callers waiting for the in-flight run
+		waiters := func() int {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			if r.call == nil {
+				return 0
+			}
+
+			return r.call.waiters
+		}
+
+		results := make(chan State, 2)
+
+		for i := 0; i < 2; i++ {
+			go func() {
+				state, _ := h.RunNow("foo")
+				results <- state
+			}()
+		}
+
+		// let the runs queue up behind the initial one
+		Eventually(waiters).Should(Equal(2))
+		close(release)
+
+		Expect((<-results).Status).To(Equal(StatusOK))
+		Expect((<-results).Status).To(Equal(StatusOK))
+		Expect(checker.StatusCallCount()).To(Equal(1))
+	})
+
+	t.Run("Should error for an unknown check", func(t *testing.T) {
+		h, _, err := setupRunners(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer h.Stop()
+
+		_, err = h.RunNow("baz")
+		Expect(err).To(Equal(ErrCheckNotFound))
+	})
+
+	t.Run("Should error if the healthcheck is not running", func(t *testing.T) {
+		h := setupNewTestHealth()
+		Expect(h.AddCheck(&Config{Name: "foo", Checker: &fakes.FakeICheckable{}, Interval: time.Hour})).To(Succeed())
+
+		_, err := h.RunNow("foo")
+		Expect(err).To(Equal(ErrNotRunning))
+	})
+}
+
+func TestRunAll(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should run all checks and return their fresh states", func(t *testing.T) {
+		cfgs := setupTestConfigs()
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
+
+		states, err := h.RunAll(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(states).To(HaveLen(len(cfgs)))
+
+		for _, cfg := range cfgs {
+			Expect(states).To(HaveKey(cfg.Name))
+			Expect(states[cfg.Name].ContiguousSuccesses).To(Equal(int64(2)))
+		}
+	})
+
+	t.Run("Should return the context error if the checks take too long", func(t *testing.T) {
+		release := make(chan struct{})
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusStub = func() (interface{}, error) {
+			<-release
+			return nil, nil
+		}
+
+		cfgs := []*Config{
+			{
+				Name:     "foo",
+				Checker:  checker,
+				Interval: time.Hour,
+			},
+		}
+
+		h, _, err := setupRunners(cfgs, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer h.Stop()
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		states, err := h.RunAll(ctx)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		Expect(states).To(BeEmpty())
+	})
+
+	t.Run("Should error if the healthcheck is not running", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		_, err := h.RunAll(context.Background())
+		Expect(err).To(Equal(ErrNotRunning))
+	})
+}
+
+func TestCheckOnce(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should run every check once and return the aggregate result", func(t *testing.T) {
+		failing := &fakes.FakeICheckable{}
+		failing.StatusReturns(nil, errors.New("things broke"))
+		passing := &fakes.FakeICheckable{}
+
+		h := setupNewTestHealth()
+		Expect(h.AddChecks([]*Config{
+			{Name: "foo", Checker: failing, Interval: testCheckInterval, Fatal: true},
+			{Name: "bar", Checker: passing, Interval: testCheckInterval},
+		})).To(Succeed())
+
+		states, failed, err := h.CheckOnce(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeTrue())
+		Expect(states).To(HaveLen(2))
+		Expect(states["foo"].Status).To(Equal(StatusFailed))
+		Expect(states["foo"].ContiguousFailures).To(Equal(int64(1)))
+		Expect(states["bar"].Status).To(Equal(StatusOK))
+
+		Expect(failing.StatusCallCount()).To(Equal(1))
+		Expect(passing.StatusCallCount()).To(Equal(1))
+
+		// nothing is started or recorded
+		Expect(h.active.val()).To(BeFalse())
+		Expect(h.safeGetStates()).To(BeEmpty())
+	})
+
+	t.Run("Should skip checks whose dependencies have failed", func(t *testing.T) {
+		network := &fakes.FakeICheckable{}
+		network.StatusReturns(nil, errors.New("network is down"))
+
+		db := &fakes.FakeICheckable{}
+
+		h := setupNewTestHealth()
+		Expect(h.AddChecks([]*Config{
+			{Name: "API", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, DependsOn: []string{"DB"}},
+			{Name: "DB", Checker: db, Interval: testCheckInterval, DependsOn: []string{"NETWORK"}, Fatal: true},
+			{Name: "NETWORK", Checker: network, Interval: testCheckInterval},
+		})).To(Succeed())
+
+		// the skipped fatal check fails the overall health
+		states, failed, err := h.CheckOnce(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeTrue())
+		Expect(states).To(HaveLen(3))
+
+		Expect(states["NETWORK"].Status).To(Equal(StatusFailed))
+		Expect(states["DB"].Status).To(Equal(StatusSkipped))
+		Expect(states["DB"].RootCause).To(Equal("NETWORK"))
+		Expect(states["API"].Status).To(Equal(StatusSkipped))
+		Expect(states["API"].RootCause).To(Equal("NETWORK"))
+		Expect(db.StatusCallCount()).To(BeZero())
+	})
+
+	t.Run("Should error if the dependencies are invalid", func(t *testing.T) {
+		h := setupNewTestHealth()
+		Expect(h.AddChecks([]*Config{
+			{Name: "foo", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, DependsOn: []string{"bar"}},
+			{Name: "bar", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, DependsOn: []string{"foo"}},
+		})).To(Succeed())
+
+		_, _, err := h.CheckOnce(context.Background())
+		Expect(err).To(Equal(ErrDependencyCycle))
+	})
+
+	t.Run("Should not disturb the states of a running healthcheck", func(t *testing.T) {
+		cfgs := []*Config{
+			{Name: "foo", Checker: &fakes.FakeICheckable{}, Interval: time.Hour},
+			{Name: "bar", Checker: &fakes.FakeICheckable{}, Interval: time.Hour},
+		}
+
+		h := setupNewTestHealth()
+		setupFakeClockRunners(h, cfgs, 4)
+		defer h.Stop()
+
+		before := h.safeGetStates()
+
+		states, failed, err := h.CheckOnce(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeFalse())
+		Expect(states).To(HaveLen(2))
+
+		Expect(h.safeGetStates()).To(Equal(before))
+	})
+
+	t.Run("Should limit the number of concurrently running checks", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			running int
+			maxSeen int
+		)
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+
+		newChecker := func() *fakes.FakeICheckable {
+			checker := &fakes.FakeICheckable{}
+			checker.StatusStub = func() (interface{}, error) {
+				mu.Lock()
+				running++
+				if running > maxSeen {
+					maxSeen = running
+				}
+				mu.Unlock()
+
+				entered <- struct{}{}
+				<-release
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+
+				return nil, nil
+			}
+
+			return checker
+		}
+
+		h := setupNewTestHealth()
+		h.CheckOnceConcurrency = 2
+
+		for i := 0; i < 6; i++ {
+			Expect(h.AddCheck(&Config{Name: fmt.Sprintf("check%d", i), Checker: newChecker(), Interval: testCheckInterval})).To(Succeed())
+		}
+
+		done := make(chan map[string]State, 1)
+		go func() {
+			states, _, _ := h.CheckOnce(context.Background())
+			done <- states
+		}()
+
+		// let the checks through two at a time
+		for i := 0; i < 3; i++ {
+			<-entered
+			<-entered
+			release <- struct{}{}
+			release <- struct{}{}
+		}
+
+		Expect(<-done).To(HaveLen(6))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(maxSeen).To(Equal(2))
+	})
+
+	t.Run("Should return the context error if the checks take too long", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusStub = func() (interface{}, error) {
+			<-release
+			return nil, nil
+		}
+
+		h := setupNewTestHealth()
+		Expect(h.AddCheck(&Config{Name: "foo", Checker: checker, Interval: testCheckInterval})).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		states, failed, err := h.CheckOnce(ctx)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		Expect(failed).To(BeTrue())
+		Expect(states).To(BeEmpty())
+	})
+}
+
+func TestMaxConcurrentChecks(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should limit the number of concurrently running checks", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			running int
+			maxSeen int
+		)
+
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+
+		newChecker := func() *fakes.FakeICheckable {
+			checker := &fakes.FakeICheckable{}
+			checker.StatusStub = func() (interface{}, error) {
+				mu.Lock()
+				running++
+				if running > maxSeen {
+					maxSeen = running
+				}
+				mu.Unlock()
+
+				started <- struct{}{}
+				<-release
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+
+				return nil, nil
+			}
+
+			return checker
+		}
+
+		clk := fakes.NewFakeClock(time.Now())
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		h.MaxConcurrentChecks = 1
+
+		Expect(h.AddChecks([]*Config{
+			{Name: "foo", Checker: newChecker(), Interval: time.Hour},
+			{Name: "bar", Checker: newChecker(), Interval: time.Hour},
+		})).To(Succeed())
+
+		Expect(h.Start()).To(Succeed())
+		defer h.Stop()
+
+		// one check takes the only worker, the other one waits for it (with
+		// its interval as the deadline)
+		<-started
+		awaitWaiters(clk, 1)
+
+		clk.Advance(time.Duration(4) * time.Millisecond)
+		close(release)
+
+		Eventually(h.safeGetStates).Should(HaveLen(2))
+
+		states := h.safeGetStates()
+		Expect(states["foo"].Status).To(Equal(StatusOK))
+		Expect(states["bar"].Status).To(Equal(StatusOK))
+
+		// one of them had to wait for the other
+		Expect(states["foo"].QueueWait + states["bar"].QueueWait).To(Equal(4 * time.Millisecond))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(maxSeen).To(Equal(1))
+	})
+
+	t.Run("Should mark a check that could not be scheduled in time as stale", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+
+		blocking := &fakes.FakeICheckable{}
+		blocking.StatusStub = func() (interface{}, error) {
+			started <- struct{}{}
+			<-release
+			return nil, nil
+		}
+
+		starved := &fakes.FakeICheckable{}
+
+		clk := fakes.NewFakeClock(time.Now())
+
+		h := setupNewTestHealth()
+		h.Clock = clk
+		h.MaxConcurrentChecks = 1
+
+		Expect(h.AddCheck(&Config{Name: "foo", Checker: blocking, Interval: time.Hour})).To(Succeed())
+		Expect(h.Start()).To(Succeed())
+		defer h.Stop()
+		defer close(release)
+
+		// let the blocking check take the only worker
+		<-started
+
+		Expect(h.AddCheck(&Config{Name: "bar", Checker: starved, Interval: time.Duration(5) * time.Millisecond, Fatal: true})).To(Succeed())
+
+		// the starved check gives up waiting for a worker after its interval
+		awaitWaiters(clk, 1)
+		clk.Advance(time.Duration(5) * time.Millisecond)
+
+		Eventually(func() string { return h.safeGetStates()["bar"].Status }).Should(Equal(StatusStale))
+
+		state := h.safeGetStates()["bar"]
+		Expect(state.Err).To(Equal(ErrCheckNotScheduled.Error()))
+		Expect(state.QueueWait).To(Equal(5 * time.Millisecond))
+		Expect(starved.StatusCallCount()).To(Equal(0))
+
+		Expect(h.Failed()).To(BeTrue())
+		Expect(h.Status()).To(Equal(StatusFailed))
+	})
+}
+
+func TestStaleness(t *testing.T) {
+	RegisterTestingT(t)
+
+	// the first run passes, the following runs hang until released
+	newHangingChecker := func(started, release chan struct{}) *fakes.FakeICheckable {
+		checker := &fakes.FakeICheckable{}
+		checker.StatusStub = func() (interface{}, error) {
+			if checker.StatusCallCount() > 1 {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+
+				<-release
+			}
+
+			return nil, nil
+		}
+
+		return checker
+	}
+
+	t.Run("Should mark a check that stopped reporting as stale", func(t *testing.T) {
+		testLogger = testlog.New()
+
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
+		h.StatusListener = &MockStaleStatusListener{}
+
+		// one timer for the next run and one for the staleness
+		clk := setupFakeClockRunners(h, []*Config{
+			{
+				Name:     "FOOCHECK",
+				Checker:  newHangingChecker(started, release),
+				Interval: time.Duration(5) * time.Millisecond,
+				MaxAge:   time.Duration(15) * time.Millisecond,
+				Fatal:    true,
+			},
+		}, 2)
+		defer h.Stop()
+		defer close(release)
+
+		// the second run hangs...
+		clk.Advance(time.Duration(5) * time.Millisecond)
+		<-started
+
+		// ... until the result is older than "MaxAge"
+		clk.Advance(time.Duration(10) * time.Millisecond)
+
+		Eventually(func() string { return h.safeGetStates()["FOOCHECK"].Status }).Should(Equal(StatusStale))
+		Expect(h.safeGetStates()["FOOCHECK"].Err).To(Equal(ErrCheckStale.Error()))
+		Expect(h.Failed()).To(BeTrue())
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("staleFOOCHECK"))
+
+		release <- struct{}{}
+
+		Eventually(func() string { return h.safeGetStates()["FOOCHECK"].Status }).Should(Equal(StatusOK))
+		Expect(h.Failed()).To(BeFalse())
+
+		// the check never failed
+		Expect(string(testLogger.Bytes())).ToNot(ContainSubstring("[DEBUG] FOOCHECK"))
+	})
+
+	t.Run("Should not mark checks as stale if disabled", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+
+		// the runner only arms the timer of its next run
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, []*Config{
+			{
+				Name:     "foo",
+				Checker:  newHangingChecker(started, release),
+				Interval: time.Duration(5) * time.Millisecond,
+				MaxAge:   -1,
+			},
+		}, 1)
+		defer h.Stop()
+		defer close(release)
+
+		clk.Advance(time.Duration(5) * time.Millisecond)
+		<-started
+
+		clk.Advance(time.Hour)
+
+		Expect(clk.Waiters()).To(BeZero())
+		Expect(h.safeGetStates()["foo"].Status).To(Equal(StatusOK))
+	})
+}
+
+func TestDependsOn(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should skip checks whose dependencies have failed", func(t *testing.T) {
+		testLogger = testlog.New()
+
+		network := &fakes.FakeICheckable{}
+		network.StatusReturns(nil, errors.New("network is down"))
+
+		db := &fakes.FakeICheckable{}
+		db.StatusReturns(nil, errors.New("db is down"))
+
+		api := &fakes.FakeICheckable{}
+
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
+		h.StatusListener = &MockStatusListener{}
+
+		clk := setupFakeClockRunners(h, []*Config{
+			{Name: "NETWORK", Checker: network, Interval: testCheckInterval},
+			{Name: "DB", Checker: db, Interval: testCheckInterval, DependsOn: []string{"NETWORK"}, Fatal: true},
+			{Name: "API", Checker: api, Interval: testCheckInterval, DependsOn: []string{"DB"}},
+		}, 6)
+		defer h.Stop()
+
+		// the dependents wait for the first results of their dependencies
+		states := h.safeGetStates()
+		Expect(states["NETWORK"].Status).To(Equal(StatusFailed))
+
+		Expect(states["DB"].Status).To(Equal(StatusSkipped))
+		Expect(states["DB"].RootCause).To(Equal("NETWORK"))
+		Expect(states["API"].Status).To(Equal(StatusSkipped))
+		Expect(states["API"].RootCause).To(Equal("NETWORK"))
+
+		Expect(h.Failed()).To(BeTrue())
+
+		// the dependents are not run while their dependency is failing
+		advance(clk, testCheckInterval, 6)
+		Expect(network.StatusCallCount()).To(Equal(2))
+		Expect(db.StatusCallCount()).To(BeZero())
+		Expect(api.StatusCallCount()).To(BeZero())
+
+		// only the root cause is reported as a failure
+		Expect(string(testLogger.Bytes())).To(ContainSubstring("[DEBUG] NETWORK"))
+		Expect(string(testLogger.Bytes())).ToNot(ContainSubstring("[DEBUG] DB"))
+		Expect(string(testLogger.Bytes())).ToNot(ContainSubstring("[DEBUG] API"))
+	})
+
+	t.Run("Should hold the first run of a check until its dependencies have reported", func(t *testing.T) {
+		network := &fakes.FakeICheckable{}
+		db := &fakes.FakeICheckable{}
+
+		// only the initial delay of the dependency is armed
+		h := setupNewTestHealth()
+		clk := setupFakeClockRunners(h, []*Config{
+			{Name: "DB", Checker: db, Interval: testCheckInterval, DependsOn: []string{"NETWORK"}},
+			{Name: "NETWORK", Checker: network, Interval: testCheckInterval, InitialDelay: time.Second},
+		}, 1)
+		defer h.Stop()
+
+		Expect(db.StatusCallCount()).To(BeZero())
+		Expect(h.safeGetStates()).To(BeEmpty())
+
+		advance(clk, time.Second, 4)
+
+		states := h.safeGetStates()
+		Expect(states["NETWORK"].Status).To(Equal(StatusOK))
+		Expect(states["DB"].Status).To(Equal(StatusOK))
+		Expect(db.StatusCallCount()).To(Equal(1))
+	})
+
+	t.Run("Should error on start if the dependencies form a cycle", func(t *testing.T) {
+		h := setupNewTestHealth()
+
+		Expect(h.AddChecks([]*Config{
+			{Name: "foo", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, DependsOn: []string{"bar"}},
+			{Name: "bar", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, DependsOn: []string{"foo"}},
+		})).To(Succeed())
+
+		Expect(h.Start()).To(Equal(ErrDependencyCycle))
+		Expect(h.active.val()).To(BeFalse())
+	})
+
+	t.Run("Should reject checks with unknown dependencies while running", func(t *testing.T) {
+		h, _, err := setupRunners(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer h.Stop()
+
+		err = h.AddCheck(&Config{Name: "baz", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, DependsOn: []string{"qux"}})
+		Expect(err).To(Equal(ErrUnknownDependency))
+		Expect(h.runners).ToNot(HaveKey("baz"))
+	})
+}
+
+func TestAggregator(t *testing.T) {
+	RegisterTestingT(t)
+
+	failing := &fakes.FakeICheckable{}
+	failing.StatusReturns(nil, errors.New("things broke"))
+
+	cfgs := []*Config{
+		{Name: "replica1", Checker: failing, Interval: testCheckInterval, Fatal: true, Groups: []string{GroupReadiness}},
+		{Name: "replica2", Checker: &fakes.FakeICheckable{}, Interval: testCheckInterval, Fatal: true, Groups: []string{GroupReadiness}},
+	}
+
+	h := setupNewTestHealth()
+	setupFakeClockRunners(h, cfgs, 4)
+	defer h.Stop()
+
+	t.Run("Should fail using the default aggregator", func(t *testing.T) {
+		Expect(h.Failed()).To(BeTrue())
+		Expect(h.FailedFor(GroupReadiness)).To(BeTrue())
+		Expect(h.Status()).To(Equal(StatusFailed))
+	})
+
+	t.Run("Should use the configured aggregator", func(t *testing.T) {
+		h.Aggregator = AllFailAggregator{}
+
+		_, failed, err := h.State()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeFalse())
+		Expect(h.Failed()).To(BeFalse())
+		Expect(h.FailedFor(GroupReadiness)).To(BeFalse())
+		Expect(h.Status()).To(Equal(StatusOK))
+
+		h.Aggregator = QuorumAggregator{MaxFailures: 0}
+		Expect(h.Failed()).To(BeTrue())
 	})
 }
@@ -0,0 +1,54 @@
+package health
+
+import (
+	"time"
+)
+
+// HistoryEntry is a single recorded result of a check, as returned by
+// "Health.History()".
+type HistoryEntry struct {
+	// Status of the run ("ok", "warn" or "failed"), before the failure and
+	// success thresholds were applied
+	Status string `json:"status"`
+
+	// Err is the error returned by the run, if any
+	Err string `json:"error,omitempty"`
+
+	// CheckTime is the time at which the run completed
+	CheckTime time.Time `json:"check_time"`
+
+	// Duration is how long the run took (in nanoseconds when marshaled)
+	Duration time.Duration `json:"duration"`
+}
+
+// history is a fixed size ring buffer of check results
+type history struct {
+	entries []HistoryEntry
+	next    int
+	full    bool
+}
+
+func newHistory(size int) *history {
+	return &history{
+		entries: make([]HistoryEntry, size),
+	}
+}
+
+// add records an entry, overwriting the oldest one if the buffer is full
+func (r *history) add(entry HistoryEntry) {
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns a copy of the recorded entries, oldest first
+func (r *history) list() []HistoryEntry {
+	if !r.full {
+		return append([]HistoryEntry{}, r.entries[:r.next]...)
+	}
+
+	return append(append([]HistoryEntry{}, r.entries[r.next:]...), r.entries[:r.next]...)
+}
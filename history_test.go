@@ -0,0 +1,41 @@
+package health
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHistoryRing(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return entries oldest first", func(t *testing.T) {
+		r := newHistory(3)
+		Expect(r.list()).To(BeEmpty())
+
+		r.add(HistoryEntry{Status: "1"})
+		r.add(HistoryEntry{Status: "2"})
+		Expect(r.list()).To(Equal([]HistoryEntry{{Status: "1"}, {Status: "2"}}))
+	})
+
+	t.Run("Should overwrite the oldest entries once full", func(t *testing.T) {
+		r := newHistory(3)
+
+		for _, s := range []string{"1", "2", "3", "4", "5"} {
+			r.add(HistoryEntry{Status: s})
+		}
+
+		Expect(r.list()).To(Equal([]HistoryEntry{{Status: "3"}, {Status: "4"}, {Status: "5"}}))
+	})
+
+	t.Run("Returned entries should not be modified by later adds", func(t *testing.T) {
+		r := newHistory(2)
+		r.add(HistoryEntry{Status: "1"})
+		r.add(HistoryEntry{Status: "2"})
+
+		entries := r.list()
+		r.add(HistoryEntry{Status: "3"})
+
+		Expect(entries).To(Equal([]HistoryEntry{{Status: "1"}, {Status: "2"}}))
+	})
+}
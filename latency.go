@@ -0,0 +1,82 @@
+package health
+
+import (
+	"sort"
+	"time"
+)
+
+// the number of recent runs latency statistics are calculated over
+const latencyWindowSize = 100
+
+// LatencyStats contains statistics about the durations of the recent runs of a
+// check (in nanoseconds when marshaled).
+type LatencyStats struct {
+	Min  time.Duration `json:"min"`
+	Max  time.Duration `json:"max"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+}
+
+// latencyWindow is a fixed size ring buffer of check durations
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func newLatencyWindow(size int) *latencyWindow {
+	return &latencyWindow{
+		samples: make([]time.Duration, size),
+	}
+}
+
+// add records a sample, overwriting the oldest one if the window is full
+func (w *latencyWindow) add(d time.Duration) {
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % len(w.samples)
+
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+// stats calculates the statistics of the samples currently in the window
+func (w *latencyWindow) stats() *LatencyStats {
+	n := w.next
+	if w.full {
+		n = len(w.samples)
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	sorted := append([]time.Duration{}, w.samples[:n]...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	return &LatencyStats{
+		Min:  sorted[0],
+		Max:  sorted[n-1],
+		Mean: total / time.Duration(n),
+		P50:  percentile(sorted, 50),
+		P95:  percentile(sorted, 95),
+		P99:  percentile(sorted, 99),
+	}
+}
+
+// returns the nearest-rank percentile of the given sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
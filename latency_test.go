@@ -0,0 +1,46 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLatencyWindow(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return nil stats for an empty window", func(t *testing.T) {
+		w := newLatencyWindow(10)
+		Expect(w.stats()).To(BeNil())
+	})
+
+	t.Run("Should calculate stats over the samples", func(t *testing.T) {
+		w := newLatencyWindow(100)
+
+		for i := 100; i > 0; i-- {
+			w.add(time.Duration(i) * time.Millisecond)
+		}
+
+		Expect(*w.stats()).To(Equal(LatencyStats{
+			Min:  time.Millisecond,
+			Max:  100 * time.Millisecond,
+			Mean: 50500 * time.Microsecond,
+			P50:  50 * time.Millisecond,
+			P95:  95 * time.Millisecond,
+			P99:  99 * time.Millisecond,
+		}))
+	})
+
+	t.Run("Should only take the most recent samples into account", func(t *testing.T) {
+		w := newLatencyWindow(2)
+		w.add(time.Second)
+		w.add(time.Millisecond)
+		w.add(3 * time.Millisecond)
+
+		stats := w.stats()
+		Expect(stats.Min).To(Equal(time.Millisecond))
+		Expect(stats.Max).To(Equal(3 * time.Millisecond))
+		Expect(stats.Mean).To(Equal(2 * time.Millisecond))
+	})
+}
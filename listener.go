@@ -0,0 +1,138 @@
+package health
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/InVisionApp/go-logger"
+)
+
+// MultiListener is an IStatusListener that fans every notification out to its
+// members, in order. It also implements the optional "IDegradedStatusListener"
+// and "IStaleStatusListener" interfaces, forwarding those notifications to the
+// members that implement them.
+//
+// A panicking member does not prevent the other members from being notified;
+// the first panic is re-raised once all members have been called.
+type MultiListener []IStatusListener
+
+// HealthCheckFailed notifies every member of the failure.
+func (m MultiListener) HealthCheckFailed(entry *State) {
+	m.each(func(l IStatusListener) {
+		l.HealthCheckFailed(entry)
+	})
+}
+
+// HealthCheckRecovered notifies every member of the recovery.
+func (m MultiListener) HealthCheckRecovered(entry *State, recordedFailures int64, failureDurationSeconds float64) {
+	m.each(func(l IStatusListener) {
+		l.HealthCheckRecovered(entry, recordedFailures, failureDurationSeconds)
+	})
+}
+
+// HealthCheckDegraded notifies every member implementing
+// "IDegradedStatusListener" of the degradation.
+func (m MultiListener) HealthCheckDegraded(entry *State) {
+	m.each(func(l IStatusListener) {
+		if dl, ok := l.(IDegradedStatusListener); ok {
+			dl.HealthCheckDegraded(entry)
+		}
+	})
+}
+
+// HealthCheckDegradationCleared notifies every member implementing
+// "IDegradedStatusListener" that the degradation cleared.
+func (m MultiListener) HealthCheckDegradationCleared(entry *State) {
+	m.each(func(l IStatusListener) {
+		if dl, ok := l.(IDegradedStatusListener); ok {
+			dl.HealthCheckDegradationCleared(entry)
+		}
+	})
+}
+
+// HealthCheckStale notifies every member implementing "IStaleStatusListener"
+// that the check went stale.
+func (m MultiListener) HealthCheckStale(entry *State) {
+	m.each(func(l IStatusListener) {
+		if sl, ok := l.(IStaleStatusListener); ok {
+			sl.HealthCheckStale(entry)
+		}
+	})
+}
+
+// calls fn for every member, re-raising the first panic (if any) afterwards
+func (m MultiListener) each(fn func(l IStatusListener)) {
+	var panicked interface{}
+
+	for _, l := range m {
+		func() {
+			defer func() {
+				if r := recover(); r != nil && panicked == nil {
+					panicked = r
+				}
+			}()
+
+			fn(l)
+		}()
+	}
+
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// AddStatusListener registers an additional status listener, notified along
+// with "Health.StatusListener" (if set). Listeners may be added at any time;
+// the optional listener interfaces ("IDegradedStatusListener",
+// "IStaleStatusListener") are honored for every listener.
+func (h *Health) AddStatusListener(l IStatusListener) {
+	if l == nil {
+		return
+	}
+
+	h.listenersLock.Lock()
+	defer h.listenersLock.Unlock()
+
+	h.listeners = append(h.listeners, l)
+}
+
+// returns "Health.StatusListener" (if set) followed by the added listeners
+func (h *Health) statusListeners() []IStatusListener {
+	h.listenersLock.Lock()
+	defer h.listenersLock.Unlock()
+
+	listeners := make([]IStatusListener, 0, len(h.listeners)+1)
+
+	if h.StatusListener != nil {
+		listeners = append(listeners, h.StatusListener)
+	}
+
+	return append(listeners, h.listeners...)
+}
+
+// calls fn for every status listener, either in its own goroutine or (with
+// "SyncStatusListeners") in place
+func (h *Health) notifyListeners(fn func(l IStatusListener)) {
+	for _, l := range h.statusListeners() {
+		if h.SyncStatusListeners {
+			h.callListener(l, fn)
+		} else {
+			go h.callListener(l, fn)
+		}
+	}
+}
+
+// calls fn for the given listener, logging (instead of propagating) a panic
+func (h *Health) callListener(l IStatusListener, fn func(l IStatusListener)) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.Logger.WithFields(log.Fields{
+				"listener": fmt.Sprintf("%T", l),
+				"panic":    fmt.Sprint(r),
+				"stack":    string(debug.Stack()),
+			}).Error("status listener panicked")
+		}
+	}()
+
+	fn(l)
+}
@@ -0,0 +1,168 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/InVisionApp/go-logger/shims/testlog"
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2/fakes"
+)
+
+// records the notifications it receives, prefixed with its id
+type recordingListener struct {
+	id    string
+	calls *[]string
+	mu    *sync.Mutex
+	panic bool
+}
+
+func (r *recordingListener) record(s string) {
+	r.mu.Lock()
+	*r.calls = append(*r.calls, r.id+":"+s)
+	r.mu.Unlock()
+
+	if r.panic {
+		panic("boom")
+	}
+}
+
+func (r *recordingListener) HealthCheckFailed(entry *State) {
+	r.record("failed " + entry.Name)
+}
+
+func (r *recordingListener) HealthCheckRecovered(entry *State, recordedFailures int64, failureDurationSeconds float64) {
+	r.record(fmt.Sprintf("recovered %s %d", entry.Name, recordedFailures))
+}
+
+type recordingDegradedListener struct {
+	*recordingListener
+}
+
+func (r recordingDegradedListener) HealthCheckDegraded(entry *State) {
+	r.record("degraded " + entry.Name)
+}
+
+func (r recordingDegradedListener) HealthCheckDegradationCleared(entry *State) {
+	r.record("cleared " + entry.Name)
+}
+
+func TestMultiListener(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should notify all members in order", func(t *testing.T) {
+		var calls []string
+		mu := &sync.Mutex{}
+
+		m := MultiListener{
+			&recordingListener{id: "a", calls: &calls, mu: mu},
+			recordingDegradedListener{&recordingListener{id: "b", calls: &calls, mu: mu}},
+		}
+
+		m.HealthCheckFailed(&State{Name: "foo"})
+		m.HealthCheckRecovered(&State{Name: "foo"}, 2, 1)
+		m.HealthCheckDegraded(&State{Name: "foo"})
+		m.HealthCheckStale(&State{Name: "foo"})
+
+		Expect(calls).To(Equal([]string{
+			"a:failed foo", "b:failed foo",
+			"a:recovered foo 2", "b:recovered foo 2",
+			"b:degraded foo",
+		}))
+	})
+
+	t.Run("Should notify the remaining members when one panics", func(t *testing.T) {
+		var calls []string
+		mu := &sync.Mutex{}
+
+		m := MultiListener{
+			&recordingListener{id: "a", calls: &calls, mu: mu, panic: true},
+			&recordingListener{id: "b", calls: &calls, mu: mu},
+		}
+
+		Expect(func() { m.HealthCheckFailed(&State{Name: "foo"}) }).To(Panic())
+		Expect(calls).To(Equal([]string{"a:failed foo", "b:failed foo"}))
+	})
+}
+
+func TestAddStatusListener(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should synchronously notify all listeners in order", func(t *testing.T) {
+		var calls []string
+		mu := &sync.Mutex{}
+
+		h := setupNewTestHealth()
+		h.SyncStatusListeners = true
+		h.StatusListener = &recordingListener{id: "a", calls: &calls, mu: mu}
+		h.AddStatusListener(&recordingListener{id: "b", calls: &calls, mu: mu})
+		h.AddStatusListener(recordingDegradedListener{&recordingListener{id: "c", calls: &calls, mu: mu}})
+		h.AddStatusListener(nil)
+
+		checker := &fakes.FakeICheckable{}
+		checker.StatusReturnsOnCall(0, nil, errors.New("down"))
+		checker.StatusReturnsOnCall(1, nil, nil)
+		checker.StatusReturnsOnCall(2, nil, &Warning{Err: errors.New("slow")})
+
+		Expect(h.AddCheck(&Config{Name: "foo", Checker: checker, Interval: time.Hour})).To(Succeed())
+		Expect(h.Start()).To(Succeed())
+		defer h.Stop()
+
+		Eventually(func() int { return checker.StatusCallCount() }).Should(Equal(1))
+
+		for i := 0; i < 2; i++ {
+			_, err := h.RunNow("foo")
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(calls).To(Equal([]string{
+			"a:failed foo", "b:failed foo", "c:failed foo",
+			"a:recovered foo 1", "b:recovered foo 1", "c:recovered foo 1",
+			"c:degraded foo",
+		}))
+	})
+
+	t.Run("Should isolate and log panicking listeners", func(t *testing.T) {
+		var calls []string
+		mu := &sync.Mutex{}
+
+		logger := testlog.New()
+
+		h := setupNewTestHealth()
+		h.Logger = logger
+		h.SyncStatusListeners = true
+		h.AddStatusListener(&recordingListener{id: "a", calls: &calls, mu: mu, panic: true})
+		h.AddStatusListener(&recordingListener{id: "b", calls: &calls, mu: mu})
+
+		h.notifyListeners(func(l IStatusListener) {
+			l.HealthCheckFailed(&State{Name: "foo"})
+		})
+
+		Expect(calls).To(Equal([]string{"a:failed foo", "b:failed foo"}))
+		Expect(logger.CallCount()).To(Equal(1))
+		Expect(string(logger.Bytes())).To(ContainSubstring("status listener panicked"))
+	})
+
+	t.Run("Should asynchronously notify listeners by default", func(t *testing.T) {
+		var calls []string
+		mu := &sync.Mutex{}
+
+		h := setupNewTestHealth()
+		h.AddStatusListener(&recordingListener{id: "a", calls: &calls, mu: mu})
+
+		h.notifyListeners(func(l IStatusListener) {
+			l.HealthCheckFailed(&State{Name: "foo"})
+		})
+
+		Eventually(func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return append([]string{}, calls...)
+		}).Should(Equal([]string{"a:failed foo"}))
+	})
+}
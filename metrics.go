@@ -0,0 +1,85 @@
+package health
+
+import (
+	"time"
+)
+
+// DefaultDurationBuckets are the upper bounds of the check duration histogram
+// exposed via "Health.Metrics()".
+var DefaultDurationBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Metrics contains the cumulative counters of a check, collected since the
+// check was added (or the healthcheck was last stopped).
+type Metrics struct {
+	// Runs is the total number of runs of the check
+	Runs int64
+
+	// Failures is the total number of failed runs of the check, before the
+	// failure and success thresholds were applied
+	Failures int64
+
+	// DurationSum is the total duration of all runs of the check
+	DurationSum time.Duration
+
+	// DurationBuckets is a cumulative histogram of the run durations, using
+	// the bounds in "DefaultDurationBuckets"
+	DurationBuckets []MetricsBucket
+}
+
+// MetricsBucket is a single bucket of a cumulative duration histogram.
+type MetricsBucket struct {
+	// UpperBound is the inclusive upper bound of the bucket
+	UpperBound time.Duration
+
+	// Count is the number of runs that took at most "UpperBound"
+	Count int64
+}
+
+// newMetrics returns empty metrics using the default duration buckets
+func newMetrics() *Metrics {
+	m := &Metrics{
+		DurationBuckets: make([]MetricsBucket, len(DefaultDurationBuckets)),
+	}
+
+	for i, b := range DefaultDurationBuckets {
+		m.DurationBuckets[i].UpperBound = b
+	}
+
+	return m
+}
+
+// observe records a single run
+func (m *Metrics) observe(stateEntry *State) {
+	m.Runs++
+	m.DurationSum += stateEntry.Duration
+
+	if stateEntry.RawStatus == StatusFailed {
+		m.Failures++
+	}
+
+	for i := range m.DurationBuckets {
+		if stateEntry.Duration <= m.DurationBuckets[i].UpperBound {
+			m.DurationBuckets[i].Count++
+		}
+	}
+}
+
+// copy returns a deep copy of the metrics
+func (m *Metrics) copy() Metrics {
+	c := *m
+	c.DurationBuckets = append([]MetricsBucket{}, m.DurationBuckets...)
+
+	return c
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2/fakes"
+)
+
+func TestMetricsObserve(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should count runs, failures and durations", func(t *testing.T) {
+		m := newMetrics()
+
+		m.observe(&State{RawStatus: StatusOK, Duration: 3 * time.Millisecond})
+		m.observe(&State{RawStatus: StatusWarn, Duration: 30 * time.Millisecond})
+		m.observe(&State{RawStatus: StatusFailed, Duration: time.Minute})
+
+		Expect(m.Runs).To(Equal(int64(3)))
+		Expect(m.Failures).To(Equal(int64(1)))
+		Expect(m.DurationSum).To(Equal(time.Minute + 33*time.Millisecond))
+
+		Expect(m.DurationBuckets).To(HaveLen(len(DefaultDurationBuckets)))
+		Expect(m.DurationBuckets[0]).To(Equal(MetricsBucket{UpperBound: 5 * time.Millisecond, Count: 1}))
+		Expect(m.DurationBuckets[3]).To(Equal(MetricsBucket{UpperBound: 50 * time.Millisecond, Count: 2}))
+		Expect(m.DurationBuckets[len(m.DurationBuckets)-1].Count).To(Equal(int64(2)))
+	})
+
+	t.Run("Should return an independent copy", func(t *testing.T) {
+		m := newMetrics()
+		c := m.copy()
+
+		m.observe(&State{RawStatus: StatusOK})
+
+		Expect(c.Runs).To(BeZero())
+		Expect(c.DurationBuckets[0].Count).To(BeZero())
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	RegisterTestingT(t)
+
+	checker := &fakes.FakeICheckable{}
+	checker.StatusReturns(nil, errors.New("broken"))
+
+	cfgs := []*Config{
+		{
+			Name:     "foo",
+			Checker:  checker,
+			Interval: testCheckInterval,
+		},
+	}
+
+	h := setupNewTestHealth()
+	setupFakeClockRunners(h, cfgs, 2)
+
+	t.Run("Should collect metrics for every check", func(t *testing.T) {
+		metrics := h.Metrics()
+		Expect(metrics).To(HaveKey("foo"))
+		Expect(metrics["foo"].Runs).To(Equal(int64(1)))
+		Expect(metrics["foo"].Failures).To(Equal(metrics["foo"].Runs))
+	})
+
+	t.Run("Should drop the metrics of a removed check", func(t *testing.T) {
+		Expect(h.RemoveCheck("foo")).To(Succeed())
+		Expect(h.Metrics()).ToNot(HaveKey("foo"))
+	})
+
+	Expect(h.Stop()).To(Succeed())
+}
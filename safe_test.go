@@ -15,9 +15,6 @@ func TestString(t *testing.T) {
 		// should be false by default
 		Expect(b.v).To(BeFalse())
 
-		// Mutex should be created
-		Expect(b.mu).ToNot(BeNil())
-
 		b.setFalse()
 		Expect(b.String()).To(Equal("false"))
 		Expect(b.val()).To(BeFalse())
@@ -0,0 +1,221 @@
+package health
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/InVisionApp/go-health/v2/clock"
+)
+
+// returns the delay before the first run of the check
+func (h *Health) initialDelay(cfg *Config) time.Duration {
+	delay := cfg.InitialDelay
+
+	if h.SpreadStart {
+		delay += randomDuration(cfg.Interval)
+	}
+
+	return delay
+}
+
+// returns a random delay to add to a scheduled run of the check
+func (cfg *Config) jitter() time.Duration {
+	max := cfg.Jitter
+
+	if max <= 0 && cfg.JitterFactor > 0 {
+		max = time.Duration(float64(cfg.Interval) * cfg.JitterFactor)
+	}
+
+	return randomDuration(max)
+}
+
+// returns a random duration in [0, max); zero if max is not positive
+func randomDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// waits for the given duration; returns false if stop is closed in the meantime
+func wait(clk clock.Clock, d time.Duration, stop <-chan struct{}) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := clk.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// Backoff configures how the interval between runs of a failing check grows.
+// The n-th consecutive failure is followed by an interval of
+// "Base * Multiplier^(n-1)", capped at "Max".
+type Backoff struct {
+	// Base is the interval after the first failure; defaults to
+	// "Config.FailingInterval" (or "Config.Interval" if that is not set)
+	Base time.Duration
+
+	// Max is the maximum interval; a zero value means no maximum
+	Max time.Duration
+
+	// Multiplier is the factor the interval grows by with every additional
+	// consecutive failure; defaults to 2
+	Multiplier float64
+}
+
+// returns the interval to wait for after a run of the check; failures is the
+// number of consecutive failed runs, including the last one
+func (cfg *Config) nextInterval(failures int64) time.Duration {
+	if failures < 1 {
+		return cfg.Interval
+	}
+
+	failingInterval := cfg.FailingInterval
+	if failingInterval <= 0 {
+		failingInterval = cfg.Interval
+	}
+
+	b := cfg.FailureBackoff
+	if b == nil {
+		return failingInterval
+	}
+
+	base := b.Base
+	if base <= 0 {
+		base = failingInterval
+	}
+
+	multiplier := b.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	interval := float64(base) * math.Pow(multiplier, float64(failures-1))
+
+	if b.Max > 0 && interval > float64(b.Max) {
+		return b.Max
+	}
+
+	if interval > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(interval)
+}
+
+// returns the time to wait for until the next scheduled run
+func (r *runner) untilNextRun() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next.IsZero() {
+		return r.cfg.Interval
+	}
+
+	return r.next.Sub(r.clock.Now())
+}
+
+// notifies the runner that the check ran outside of its schedule
+func (r *runner) reschedule() {
+	select {
+	case r.rescheduled <- struct{}{}:
+	default:
+	}
+}
+
+// notifies the dependents of the check that its first state was recorded
+func (r *runner) markRecorded() {
+	r.recordedOnce.Do(func() {
+		close(r.recorded)
+	})
+}
+
+// waits until every dependency of the check has recorded its first state;
+// returns false if the runner is stopped in the meantime
+func (r *runner) awaitDependencies() bool {
+	for _, recorded := range r.dependencies {
+		select {
+		case <-recorded:
+		case <-r.stop:
+			return false
+		}
+	}
+
+	return true
+}
+
+// waits for a free worker in the pool (if any) for at most the interval of the
+// check; returns how long it waited and whether a worker was acquired. An
+// acquired worker must be released via "releaseWorker()".
+func (r *runner) acquireWorker() (time.Duration, bool) {
+	if r.pool == nil {
+		return 0, true
+	}
+
+	start := r.clock.Now()
+
+	// fast path, a worker is free
+	select {
+	case r.pool <- struct{}{}:
+		return r.clock.Since(start), true
+	default:
+	}
+
+	var deadline <-chan time.Time
+
+	if r.cfg.Interval > 0 {
+		timer := r.clock.NewTimer(r.cfg.Interval)
+		defer timer.Stop()
+
+		deadline = timer.C()
+	}
+
+	select {
+	case r.pool <- struct{}{}:
+		return r.clock.Since(start), true
+	case <-deadline:
+	case <-r.stop:
+	case <-r.ctx.Done():
+	}
+
+	return r.clock.Since(start), false
+}
+
+// returns the worker acquired via "acquireWorker()" to the pool
+func (r *runner) releaseWorker() {
+	if r.pool != nil {
+		<-r.pool
+	}
+}
+
+// DefaultMaxAgeFactor is the multiple of the time between two runs of a check
+// after which its result is considered stale, unless "Config.MaxAge" is set.
+const DefaultMaxAgeFactor = 3
+
+// returns the age after which the given state is considered stale; zero if
+// the staleness detection is disabled
+func (cfg *Config) maxAge(stateEntry *State) time.Duration {
+	if cfg.MaxAge != 0 {
+		if cfg.MaxAge < 0 {
+			return 0
+		}
+
+		return cfg.MaxAge
+	}
+
+	gap := stateEntry.NextCheckTime.Sub(stateEntry.CheckTime)
+	if gap <= 0 {
+		gap = cfg.Interval
+	}
+
+	return DefaultMaxAgeFactor * gap
+}
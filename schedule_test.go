@@ -0,0 +1,147 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/InVisionApp/go-health/v2/clock"
+)
+
+func TestJitter(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should not jitter by default", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second}
+		Expect(cfg.jitter()).To(BeZero())
+	})
+
+	t.Run("Should stay within the configured jitter", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second, Jitter: 10 * time.Millisecond}
+
+		for i := 0; i < 100; i++ {
+			Expect(cfg.jitter()).To(BeNumerically("<", 10*time.Millisecond))
+		}
+	})
+
+	t.Run("Should derive the jitter from the interval using the jitter factor", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second, JitterFactor: 0.01}
+
+		for i := 0; i < 100; i++ {
+			Expect(cfg.jitter()).To(BeNumerically("<", 10*time.Millisecond))
+		}
+	})
+}
+
+func TestInitialDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should use the configured initial delay", func(t *testing.T) {
+		h := setupNewTestHealth()
+		Expect(h.initialDelay(&Config{Interval: time.Second, InitialDelay: time.Minute})).To(Equal(time.Minute))
+	})
+
+	t.Run("Should spread the initial run across the interval", func(t *testing.T) {
+		h := setupNewTestHealth()
+		h.SpreadStart = true
+
+		for i := 0; i < 100; i++ {
+			delay := h.initialDelay(&Config{Interval: time.Second, InitialDelay: time.Minute})
+			Expect(delay).To(BeNumerically(">=", time.Minute))
+			Expect(delay).To(BeNumerically("<", time.Minute+time.Second))
+		}
+	})
+}
+
+func TestWait(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should return true once the duration has passed", func(t *testing.T) {
+		Expect(wait(clock.New(), time.Millisecond, make(chan struct{}))).To(BeTrue())
+	})
+
+	t.Run("Should return false if stopped in the meantime", func(t *testing.T) {
+		stop := make(chan struct{})
+		close(stop)
+
+		Expect(wait(clock.New(), time.Hour, stop)).To(BeFalse())
+	})
+}
+
+func TestNextInterval(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("Should use the interval without a backoff", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second}
+
+		Expect(cfg.nextInterval(0)).To(Equal(time.Second))
+		Expect(cfg.nextInterval(5)).To(Equal(time.Second))
+	})
+
+	t.Run("Should back off while the check keeps failing", func(t *testing.T) {
+		cfg := &Config{
+			Interval: time.Second,
+			FailureBackoff: &Backoff{
+				Base:       2 * time.Second,
+				Max:        time.Minute,
+				Multiplier: 3,
+			},
+		}
+
+		Expect(cfg.nextInterval(0)).To(Equal(time.Second))
+		Expect(cfg.nextInterval(1)).To(Equal(2 * time.Second))
+		Expect(cfg.nextInterval(2)).To(Equal(6 * time.Second))
+		Expect(cfg.nextInterval(3)).To(Equal(18 * time.Second))
+		Expect(cfg.nextInterval(4)).To(Equal(54 * time.Second))
+		Expect(cfg.nextInterval(5)).To(Equal(time.Minute))
+		Expect(cfg.nextInterval(1000)).To(Equal(time.Minute))
+	})
+
+	t.Run("Should use the failing interval while the check is failing", func(t *testing.T) {
+		cfg := &Config{Interval: time.Minute, FailingInterval: time.Second}
+
+		Expect(cfg.nextInterval(0)).To(Equal(time.Minute))
+		Expect(cfg.nextInterval(1)).To(Equal(time.Second))
+		Expect(cfg.nextInterval(5)).To(Equal(time.Second))
+	})
+
+	t.Run("Should back off starting from the failing interval", func(t *testing.T) {
+		cfg := &Config{Interval: time.Minute, FailingInterval: time.Second, FailureBackoff: &Backoff{}}
+
+		Expect(cfg.nextInterval(1)).To(Equal(time.Second))
+		Expect(cfg.nextInterval(2)).To(Equal(2 * time.Second))
+	})
+
+	t.Run("Should default to doubling the interval", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second, FailureBackoff: &Backoff{}}
+
+		Expect(cfg.nextInterval(1)).To(Equal(time.Second))
+		Expect(cfg.nextInterval(3)).To(Equal(4 * time.Second))
+		Expect(cfg.nextInterval(1000)).To(BeNumerically(">", 0))
+	})
+}
+
+func TestMaxAge(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Now()
+
+	t.Run("Should use the configured max age", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second, MaxAge: time.Minute}
+		Expect(cfg.maxAge(&State{CheckTime: now, NextCheckTime: now.Add(time.Second)})).To(Equal(time.Minute))
+	})
+
+	t.Run("Should default to a multiple of the time until the next run", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second}
+
+		Expect(cfg.maxAge(&State{CheckTime: now, NextCheckTime: now.Add(time.Second)})).To(Equal(3 * time.Second))
+		Expect(cfg.maxAge(&State{CheckTime: now, NextCheckTime: now.Add(time.Minute)})).To(Equal(3 * time.Minute))
+		Expect(cfg.maxAge(&State{CheckTime: now})).To(Equal(3 * time.Second))
+	})
+
+	t.Run("Should be disabled by a negative max age", func(t *testing.T) {
+		cfg := &Config{Interval: time.Second, MaxAge: -1}
+		Expect(cfg.maxAge(&State{CheckTime: now})).To(BeZero())
+	})
+}